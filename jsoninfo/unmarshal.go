@@ -106,6 +106,8 @@ func (decoder *ObjectDecoder) DecodeStructFieldsAndExtensions(value interface{})
 				if field.MultipleFields {
 					i := fieldIndex + 1
 					if i < len(fields) && fields[i].JSONName == field.JSONName {
+						// Undo what json.Unmarshal allocated before failing
+						reflection.FieldByIndex(field.Index).Set(reflect.Zero(field.Type))
 						continue
 					}
 				}
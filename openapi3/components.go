@@ -3,6 +3,7 @@ package openapi3
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"regexp"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
@@ -26,6 +27,14 @@ func NewComponents() Components {
 	return Components{}
 }
 
+func (components *Components) isEmpty() bool {
+	return len(components.Schemas) == 0 && len(components.Parameters) == 0 &&
+		len(components.Headers) == 0 && len(components.RequestBodies) == 0 &&
+		len(components.Responses) == 0 && len(components.SecuritySchemes) == 0 &&
+		len(components.Examples) == 0 && len(components.Links) == 0 &&
+		len(components.Callbacks) == 0
+}
+
 func (components *Components) MarshalJSON() ([]byte, error) {
 	return jsoninfo.MarshalStrictStruct(components)
 }
@@ -35,56 +44,74 @@ func (components *Components) UnmarshalJSON(data []byte) error {
 }
 
 func (components *Components) Validate(ctx context.Context) (err error) {
-	for k, v := range components.Schemas {
-		if err = ValidateIdentifier(k); err != nil {
-			return
-		}
-		if err = v.Validate(ctx); err != nil {
+	for _, k := range sortedMapKeys(reflect.ValueOf(components.Schemas)) {
+		v := components.Schemas[k]
+		if err = validateAt(ctx, func(ctx context.Context) error {
+			if err := ValidateIdentifier(k); err != nil {
+				return err
+			}
+			return v.Validate(ctx)
+		}, "schemas", k); err != nil {
 			return
 		}
 	}
 
-	for k, v := range components.Parameters {
-		if err = ValidateIdentifier(k); err != nil {
-			return
-		}
-		if err = v.Validate(ctx); err != nil {
+	for _, k := range sortedMapKeys(reflect.ValueOf(components.Parameters)) {
+		v := components.Parameters[k]
+		if err = validateAt(ctx, func(ctx context.Context) error {
+			if err := ValidateIdentifier(k); err != nil {
+				return err
+			}
+			return v.Validate(ctx)
+		}, "parameters", k); err != nil {
 			return
 		}
 	}
 
-	for k, v := range components.RequestBodies {
-		if err = ValidateIdentifier(k); err != nil {
-			return
-		}
-		if err = v.Validate(ctx); err != nil {
+	for _, k := range sortedMapKeys(reflect.ValueOf(components.RequestBodies)) {
+		v := components.RequestBodies[k]
+		if err = validateAt(ctx, func(ctx context.Context) error {
+			if err := ValidateIdentifier(k); err != nil {
+				return err
+			}
+			return v.Validate(ctx)
+		}, "requestBodies", k); err != nil {
 			return
 		}
 	}
 
-	for k, v := range components.Responses {
-		if err = ValidateIdentifier(k); err != nil {
-			return
-		}
-		if err = v.Validate(ctx); err != nil {
+	for _, k := range sortedMapKeys(reflect.ValueOf(components.Responses)) {
+		v := components.Responses[k]
+		if err = validateAt(ctx, func(ctx context.Context) error {
+			if err := ValidateIdentifier(k); err != nil {
+				return err
+			}
+			return v.Validate(ctx)
+		}, "responses", k); err != nil {
 			return
 		}
 	}
 
-	for k, v := range components.Headers {
-		if err = ValidateIdentifier(k); err != nil {
-			return
-		}
-		if err = v.Validate(ctx); err != nil {
+	for _, k := range sortedMapKeys(reflect.ValueOf(components.Headers)) {
+		v := components.Headers[k]
+		if err = validateAt(ctx, func(ctx context.Context) error {
+			if err := ValidateIdentifier(k); err != nil {
+				return err
+			}
+			return v.Validate(ctx)
+		}, "headers", k); err != nil {
 			return
 		}
 	}
 
-	for k, v := range components.SecuritySchemes {
-		if err = ValidateIdentifier(k); err != nil {
-			return
-		}
-		if err = v.Validate(ctx); err != nil {
+	for _, k := range sortedMapKeys(reflect.ValueOf(components.SecuritySchemes)) {
+		v := components.SecuritySchemes[k]
+		if err = validateAt(ctx, func(ctx context.Context) error {
+			if err := ValidateIdentifier(k); err != nil {
+				return err
+			}
+			return v.Validate(ctx)
+		}, "securitySchemes", k); err != nil {
 			return
 		}
 	}
@@ -2,6 +2,7 @@ package openapi3
 
 import (
 	"context"
+	"reflect"
 	"strings"
 )
 
@@ -86,6 +87,15 @@ func (content Content) Get(mime string) *MediaType {
 	if v := content[mime]; v != nil {
 		return v
 	}
+	// A structured syntax suffix (RFC 6839) names the format of the
+	// x/y+suffix pattern: try x/suffix (e.g. application/json).
+	if i = strings.LastIndexByte(mime, '+'); i >= 0 {
+		if j := strings.IndexByte(mime, '/'); j >= 0 && j < i {
+			if v := content[mime[:j+1]+mime[i+1:]]; v != nil {
+				return v
+			}
+		}
+	}
 	// If the x/y pattern has no specific match then we
 	// try the x/* pattern.
 	i = strings.IndexByte(mime, '/')
@@ -105,9 +115,9 @@ func (content Content) Get(mime string) *MediaType {
 }
 
 func (value Content) Validate(ctx context.Context) error {
-	for _, v := range value {
+	for _, mime := range sortedMapKeys(reflect.ValueOf(value)) {
 		// Validate MediaType
-		if err := v.Validate(ctx); err != nil {
+		if err := validateAt(ctx, value[mime].Validate, mime); err != nil {
 			return err
 		}
 	}
@@ -99,6 +99,18 @@ func TestContent_Get(t *testing.T) {
 			mime:    "",
 			want:    fallback,
 		},
+		{
+			name:    "structured syntax suffix match",
+			content: content,
+			mime:    "application/vnd.acme.order+json;version=2",
+			want:    stripped,
+		},
+		{
+			name:    "structured syntax suffix without match",
+			content: contentWithoutWildcards,
+			mime:    "application/merge-patch+xml",
+			want:    nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
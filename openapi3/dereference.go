@@ -0,0 +1,197 @@
+package openapi3
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DereferenceOption describes options a user has when dereferencing a document.
+type DereferenceOption func(*dereferenceSettings)
+
+type dereferenceSettings struct {
+	failOnRecursiveRefs bool
+}
+
+// FailOnRecursiveRefs makes Dereference return an error on references that
+// loop back to a value they are part of (e.g. a tree schema), instead of
+// keeping the reference at the point the cycle closes.
+func FailOnRecursiveRefs() DereferenceOption {
+	return func(s *dereferenceSettings) { s.failOnRecursiveRefs = true }
+}
+
+// Dereference returns a deep copy of the document where references are replaced
+// by the values they point to: every Ref field is cleared and its Value inlined.
+//
+// References that loop back to a value they are part of cannot be inlined.
+// They are kept as is, with their Value pointing to the copy, unless
+// the FailOnRecursiveRefs option is given.
+// Since only those references are kept, call InternalizeRefs first
+// for the output to be usable on its own when they point to other files.
+//
+// The document must have been loaded with its references resolved.
+func (doc *T) Dereference(opts ...DereferenceOption) (*T, error) {
+	settings := &dereferenceSettings{}
+	for _, opt := range opts {
+		opt(settings)
+	}
+	d := &dereferencer{
+		settings: settings,
+		copies:   make(map[pointer]reflect.Value),
+		inlining: make(map[pointer]struct{}),
+	}
+	copied, err := d.copy(reflect.ValueOf(doc))
+	if err != nil {
+		return nil, err
+	}
+	return copied.Interface().(*T), nil
+}
+
+type dereferencer struct {
+	settings *dereferenceSettings
+
+	// copies of the pointers already copied, so that shared values stay shared
+	copies map[pointer]reflect.Value
+
+	// referenced values being inlined
+	inlining map[pointer]struct{}
+}
+
+// pointer identifies a pointer of a given type,
+// as a struct and its first field share the same address.
+type pointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func pointerOf(v reflect.Value) pointer {
+	return pointer{addr: v.Pointer(), typ: v.Type()}
+}
+
+var refValueType = reflect.TypeOf("")
+
+// isRef returns whether a struct type is one of the *Ref types: a Ref string and a Value pointer.
+func isRef(t reflect.Type) bool {
+	if t.NumField() != 2 {
+		return false
+	}
+	ref, value := t.Field(0), t.Field(1)
+	return ref.Name == "Ref" && ref.Type == refValueType &&
+		value.Name == "Value" && value.Type.Kind() == reflect.Ptr
+}
+
+func (d *dereferencer) copy(v reflect.Value) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, nil
+		}
+		if c, ok := d.copies[pointerOf(v)]; ok {
+			return c, nil
+		}
+		c := reflect.New(v.Type().Elem())
+		d.copies[pointerOf(v)] = c
+		if v.Elem().Kind() == reflect.Struct && isRef(v.Elem().Type()) {
+			return c, d.copyRef(c.Elem(), v.Elem())
+		}
+		e, err := d.copy(v.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		c.Elem().Set(e)
+		return c, nil
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, nil
+		}
+		e, err := d.copy(v.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(e)
+		return c, nil
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				// Unexported fields hold caches, but for the presence of a null const
+				continue
+			}
+			f, err := d.copy(v.Field(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.Field(i).Set(f)
+		}
+		if schema, ok := c.Addr().Interface().(*Schema); ok {
+			schema.constSet = v.Interface().(Schema).constSet
+		}
+		return c, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v, nil
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			e, err := d.copy(iter.Value())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.SetMapIndex(iter.Key(), e)
+		}
+		return c, nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v, nil
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			e, err := d.copy(v.Index(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.Index(i).Set(e)
+		}
+		return c, nil
+
+	default:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		return c, nil
+	}
+}
+
+// copyRef sets c to an inlined copy of the reference v.
+func (d *dereferencer) copyRef(c, v reflect.Value) error {
+	ref, value := v.Field(0).String(), v.Field(1)
+	if value.IsNil() {
+		if ref != "" {
+			return foundUnresolvedRef(ref)
+		}
+		return nil
+	}
+
+	if _, ok := d.inlining[pointerOf(value)]; ok {
+		if d.settings.failOnRecursiveRefs {
+			return fmt.Errorf("cannot dereference recursive reference %q", ref)
+		}
+		// The copy of the value is being built higher up the stack
+		c.Field(0).SetString(ref)
+		c.Field(1).Set(d.copies[pointerOf(value)])
+		return nil
+	}
+
+	d.inlining[pointerOf(value)] = struct{}{}
+	defer delete(d.inlining, pointerOf(value))
+	copied, err := d.copy(value)
+	if err != nil {
+		return err
+	}
+	c.Field(1).Set(copied)
+	return nil
+}
@@ -0,0 +1,114 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDereference(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info:
+  title: Dereference
+  version: 1.0.0
+paths:
+  /trees:
+    post:
+      parameters:
+      - $ref: '#/components/parameters/Depth'
+      requestBody:
+        $ref: '#/components/requestBodies/Tree'
+      responses:
+        '200':
+          $ref: '#/components/responses/Tree'
+components:
+  parameters:
+    Depth:
+      name: depth
+      in: query
+      schema:
+        $ref: '#/components/schemas/Depth'
+  requestBodies:
+    Tree:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Tree'
+  responses:
+    Tree:
+      description: A tree
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Tree'
+  schemas:
+    Depth:
+      type: integer
+      minimum: 1
+    Tree:
+      type: object
+      properties:
+        depth:
+          $ref: '#/components/schemas/Depth'
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Tree'
+`)
+	loader := NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	dereferenced, err := doc.Dereference()
+	require.NoError(t, err)
+	require.NoError(t, dereferenced.Validate(loader.Context))
+
+	op := dereferenced.Paths["/trees"].Post
+	require.Empty(t, op.Parameters[0].Ref)
+	require.Empty(t, op.Parameters[0].Value.Schema.Ref)
+	require.Equal(t, "integer", op.Parameters[0].Value.Schema.Value.Type)
+	require.Empty(t, op.RequestBody.Ref)
+	tree := op.RequestBody.Value.Content.Get("application/json").Schema
+	require.Empty(t, tree.Ref)
+	require.Empty(t, tree.Value.Properties["depth"].Ref)
+	require.Empty(t, op.Responses.Get(200).Ref)
+
+	// The cycle is closed with the original reference
+	children := tree.Value.Properties["children"].Value.Items
+	require.Equal(t, "#/components/schemas/Tree", children.Ref)
+	require.Same(t, tree.Value, children.Value)
+
+	data, err := json.Marshal(dereferenced)
+	require.NoError(t, err)
+	// One per copy of the Tree schema: in the operation and in the components
+	require.Equal(t, 5, strings.Count(string(data), `"$ref"`))
+
+	// The copy is independent of the original
+	tree.Value.Properties["depth"].Value.Min = nil
+	require.NotNil(t, doc.Components.Schemas["Depth"].Value.Min)
+	require.Equal(t, "#/components/schemas/Tree", doc.Paths["/trees"].Post.RequestBody.Value.Content.Get("application/json").Schema.Ref)
+
+	_, err = doc.Dereference(FailOnRecursiveRefs())
+	require.EqualError(t, err, `cannot dereference recursive reference "#/components/schemas/Tree"`)
+}
+
+func TestDereferenceExternalRefs(t *testing.T) {
+	loader := NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile("testdata/recursiveRef/openapi.yml")
+	require.NoError(t, err)
+
+	dereferenced, err := doc.Dereference(FailOnRecursiveRefs())
+	require.NoError(t, err)
+	data, err := json.Marshal(dereferenced)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"$ref"`)
+
+	loaded, err := NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	require.NoError(t, loaded.Validate(loader.Context))
+}
@@ -0,0 +1,160 @@
+package openapi3
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExampleError describes an example or default value that does not match its schema.
+type ExampleError struct {
+	// Pointer is the JSON pointer of the value in the document
+	// (e.g. "/paths/~1pets/get/responses/200/content/application~1json/example").
+	Pointer string
+	Err     error
+}
+
+func (err *ExampleError) Error() string {
+	return fmt.Sprintf("example %q %s", err.Pointer, err.reason())
+}
+
+func (err *ExampleError) reason() string {
+	reason := err.Err.Error()
+	if e, ok := err.Err.(*SchemaError); ok && e.Origin == nil {
+		reason = e.Reason
+		if path := e.JSONPointer(); len(path) != 0 {
+			reason = fmt.Sprintf("error at %q: %s", "/"+strings.Join(path, "/"), reason)
+		}
+	}
+	return "does not match its schema: " + reason
+}
+
+func (err *ExampleError) Unwrap() error {
+	return err.Err
+}
+
+// validateExamples checks the examples and defaults of a document against their schemas.
+func validateExamples(doc *T) error {
+	v := &exampleValidator{
+		visited:   make(map[interface{}]struct{}),
+		inRequest: make(map[interface{}]bool),
+	}
+	doc.Walk(&Visitor{
+		OnSchema:      v.schema,
+		OnParameter:   v.parameter,
+		OnHeader:      v.header,
+		OnRequestBody: v.requestBody,
+		OnMediaType:   v.mediaType,
+	})
+	if len(v.errs) != 0 {
+		return v.errs
+	}
+	return nil
+}
+
+type exampleValidator struct {
+	errs MultiError
+
+	// values already checked, so each is reported once where it is defined
+	visited map[interface{}]struct{}
+
+	// whether the media types and headers reached are parts of requests rather than responses
+	inRequest map[interface{}]bool
+}
+
+// visit returns whether value has not been checked yet, and marks it checked.
+func (v *exampleValidator) visit(value interface{}) bool {
+	if _, ok := v.visited[value]; ok {
+		return false
+	}
+	v.visited[value] = struct{}{}
+	return true
+}
+
+func (v *exampleValidator) check(pointer string, value interface{}, schema *SchemaRef, opts ...SchemaValidationOption) {
+	if value == nil || schema == nil || schema.Value == nil {
+		return
+	}
+	if err := schema.Value.VisitJSON(value, opts...); err != nil {
+		v.errs = append(v.errs, &ExampleError{Pointer: pointer, Err: err})
+	}
+}
+
+func (v *exampleValidator) examples(pointer string, examples Examples, schema *SchemaRef, opts ...SchemaValidationOption) {
+	for _, name := range sortedMapKeys(reflect.ValueOf(examples)) {
+		if ref := examples[name]; ref != nil && ref.Value != nil {
+			v.check(JoinJSONPointer(pointer, "examples", name, "value"), ref.Value.Value, schema, opts...)
+		}
+	}
+}
+
+// direction returns the option validating values of requests or responses.
+func direction(inRequest bool) SchemaValidationOption {
+	if inRequest {
+		return VisitAsRequest()
+	}
+	return VisitAsResponse()
+}
+
+func (v *exampleValidator) schema(pointer string, ref *SchemaRef) WalkAction {
+	if ref.Value != nil && v.visit(ref.Value) {
+		v.check(JoinJSONPointer(pointer, "example"), ref.Value.Example, ref)
+		v.check(JoinJSONPointer(pointer, "default"), ref.Value.Default, ref)
+	}
+	return WalkContinue
+}
+
+func (v *exampleValidator) parameter(pointer string, ref *ParameterRef) WalkAction {
+	if ref.Value != nil {
+		v.checkParameter(pointer, ref.Value, true)
+	}
+	return WalkContinue
+}
+
+// header checks a header of a response, or of a part of a request body.
+func (v *exampleValidator) header(pointer string, ref *HeaderRef) WalkAction {
+	if ref.Value != nil {
+		v.checkParameter(pointer, &ref.Value.Parameter, v.inRequest[ref.Value])
+	}
+	return WalkContinue
+}
+
+func (v *exampleValidator) checkParameter(pointer string, parameter *Parameter, inRequest bool) {
+	if !v.visit(parameter) {
+		return
+	}
+	v.check(JoinJSONPointer(pointer, "example"), parameter.Example, parameter.Schema, direction(inRequest))
+	v.examples(pointer, parameter.Examples, parameter.Schema, direction(inRequest))
+	for _, mediaType := range parameter.Content {
+		v.inRequest[mediaType] = inRequest
+	}
+}
+
+func (v *exampleValidator) requestBody(pointer string, ref *RequestBodyRef) WalkAction {
+	if ref.Value != nil {
+		for _, mediaType := range ref.Value.Content {
+			v.inRequest[mediaType] = true
+		}
+	}
+	return WalkContinue
+}
+
+func (v *exampleValidator) mediaType(pointer string, mediaType *MediaType) WalkAction {
+	if !v.visit(mediaType) {
+		return WalkContinue
+	}
+	inRequest := v.inRequest[mediaType]
+	v.check(JoinJSONPointer(pointer, "example"), mediaType.Example, mediaType.Schema, direction(inRequest))
+	v.examples(pointer, mediaType.Examples, mediaType.Schema, direction(inRequest))
+	for _, encoding := range mediaType.Encoding {
+		if encoding == nil {
+			continue
+		}
+		for _, header := range encoding.Headers {
+			if header != nil && header.Value != nil {
+				v.inRequest[header.Value] = inRequest
+			}
+		}
+	}
+	return WalkContinue
+}
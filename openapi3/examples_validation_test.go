@@ -0,0 +1,189 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExamples(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info:
+  title: Examples
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          maximum: 100
+          default: 1000
+        example: 10
+      - $ref: '#/components/parameters/Kind'
+      responses:
+        '200':
+          description: Pets
+          headers:
+            X-Total:
+              schema:
+                type: integer
+              example: many
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+              examples:
+                ok:
+                  value:
+                  - id: 1
+                    name: Rex
+                bad:
+                  value:
+                  - name: Rex
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+            example:
+              name: Rex
+      responses:
+        '201':
+          description: Created
+components:
+  parameters:
+    Kind:
+      name: kind
+      in: query
+      schema:
+        type: string
+        enum: [cat, dog]
+      example: bird
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          type: string
+          example: 42
+`)
+	loader := NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	err = doc.Validate(context.Background())
+	require.NoError(t, err)
+
+	err = doc.Validate(context.Background(), EnableExamplesValidation())
+	require.Error(t, err)
+	var pointers []string
+	for _, e := range err.(MultiError) {
+		var exampleErr *ExampleError
+		require.True(t, errors.As(e, &exampleErr))
+		pointers = append(pointers, exampleErr.Pointer)
+	}
+	require.Equal(t, []string{
+		"/components/schemas/Pet/properties/name/example",
+		"/components/parameters/Kind/example",
+		"/paths/~1pets/get/parameters/0/schema/default",
+		"/paths/~1pets/get/responses/200/headers/X-Total/example",
+		"/paths/~1pets/get/responses/200/content/application~1json/examples/bad/value",
+	}, pointers)
+	require.Contains(t, err.Error(), `example "/paths/~1pets/get/responses/200/content/application~1json/examples/bad/value" does not match its schema: error at "/0/id": property "id" is missing`)
+}
+
+func TestValidateExamplesInDefs(t *testing.T) {
+	spec := []byte(`
+openapi: 3.1.0
+info:
+  title: Examples
+  version: 1.0.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        age:
+          $ref: '#/components/schemas/Pet/$defs/Age'
+      $defs:
+        Age:
+          type: integer
+          example: old
+`)
+	loader := NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	err = doc.Validate(context.Background(), EnableExamplesValidation())
+	require.Error(t, err)
+	require.Len(t, err.(MultiError), 1)
+	require.Equal(t, "/components/schemas/Pet/$defs/Age/example", err.(MultiError)[0].(*ExampleError).Pointer)
+}
+
+func TestValidateExamplesDirections(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info:
+  title: Examples
+  version: 1.0.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+            encoding:
+              photo:
+                headers:
+                  X-Photo:
+                    schema:
+                      type: object
+                      required: [id]
+                      properties:
+                        id:
+                          type: integer
+                          readOnly: true
+                    example: {}
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Pet:
+              schema:
+                $ref: '#/components/schemas/Secret'
+              example: {}
+components:
+  schemas:
+    Secret:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+`)
+	loader := NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	// Read-only properties are only required in responses
+	err = doc.Validate(context.Background(), EnableExamplesValidation())
+	require.Error(t, err)
+	require.Len(t, err.(MultiError), 1)
+	require.Equal(t, "/paths/~1pets/post/responses/200/headers/X-Pet/example", err.(MultiError)[0].(*ExampleError).Pointer)
+}
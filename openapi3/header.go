@@ -75,13 +75,13 @@ func (value *Header) Validate(ctx context.Context) error {
 		return fmt.Errorf("header schema is invalid: %v", e)
 	}
 	if schema := value.Schema; schema != nil {
-		if err := schema.Validate(ctx); err != nil {
+		if err := validateAt(ctx, schema.Validate, "schema"); err != nil {
 			return fmt.Errorf("header schema is invalid: %v", err)
 		}
 	}
 
 	if content := value.Content; content != nil {
-		if err := content.Validate(ctx); err != nil {
+		if err := validateAt(ctx, content.Validate, "content"); err != nil {
 			return fmt.Errorf("header content is invalid: %v", err)
 		}
 	}
@@ -0,0 +1,274 @@
+package openapi3
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultRefNameResolver names the component an external reference is moved to
+// after the last segment of its fragment (e.g. "Pet" for "pets.yml#/components/schemas/Pet"),
+// or after the referenced file when there is no fragment (e.g. "Pet" for "schemas/Pet.yml").
+// Characters not allowed in component names are replaced with underscores.
+func DefaultRefNameResolver(ref string) string {
+	name := ref
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		name = unescapeRefString(path.Base(ref[i+1:]))
+	} else {
+		name = path.Base(ref)
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 128 && IdentifierRegExp.MatchString(string(r)) {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// InternalizeRefs bundles a document loaded with external references into
+// a single self-contained document.
+//
+// Every component referenced from another file is copied under Components
+// and its reference rewritten to point there, so that marshaling the document
+// produces one file that no longer depends on the others.
+// Components are named with refNameResolver, or DefaultRefNameResolver if nil;
+// a numeric suffix is appended when the name is already taken by another component.
+// Components of the document that are themselves external references are inlined.
+//
+// The document must have been loaded with its references resolved.
+func (doc *T) InternalizeRefs(refNameResolver func(ref string) string) {
+	if refNameResolver == nil {
+		refNameResolver = DefaultRefNameResolver
+	}
+	ri := &refInternalizer{
+		doc:          doc,
+		resolveName:  refNameResolver,
+		componentRef: make(map[interface{}]string),
+		visited:      make(map[interface{}]struct{}),
+	}
+
+	// Register the document's own components first so that references
+	// to them, wherever they come from, point back at them.
+	for _, kind := range componentKinds {
+		m := ri.components(kind)
+		for _, name := range sortedMapKeys(m) {
+			ref := m.MapIndex(reflect.ValueOf(name))
+			if ref.IsNil() {
+				continue
+			}
+			refField, value := ref.Elem().FieldByName("Ref"), ref.Elem().FieldByName("Value")
+			if value.IsNil() {
+				continue
+			}
+			if r := refField.String(); r != "" {
+				if strings.HasPrefix(r, "#/components/") {
+					continue
+				}
+				refField.SetString("")
+			}
+			if _, ok := ri.componentRef[value.Interface()]; !ok {
+				ri.componentRef[value.Interface()] = "#/components/" + kind + "/" + name
+			}
+		}
+	}
+
+	doc.Walk(&Visitor{
+		OnPathItem: func(pointer string, pathItem *PathItem) WalkAction {
+			// The loader already merged referenced path items in place
+			if !strings.HasPrefix(pathItem.Ref, "#") {
+				pathItem.Ref = ""
+			}
+			return WalkContinue
+		},
+		OnParameter: func(pointer string, ref *ParameterRef) WalkAction {
+			ref.Ref = ri.internalize("parameters", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnRequestBody: func(pointer string, ref *RequestBodyRef) WalkAction {
+			ref.Ref = ri.internalize("requestBodies", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnResponse: func(pointer string, ref *ResponseRef) WalkAction {
+			ref.Ref = ri.internalize("responses", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnHeader: func(pointer string, ref *HeaderRef) WalkAction {
+			ref.Ref = ri.internalize("headers", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnSchema: func(pointer string, ref *SchemaRef) WalkAction {
+			ri.schemaRef(ref)
+			return WalkContinue
+		},
+		OnExample: func(pointer string, ref *ExampleRef) WalkAction {
+			ref.Ref = ri.internalize("examples", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnLink: func(pointer string, ref *LinkRef) WalkAction {
+			ref.Ref = ri.internalize("links", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnCallback: func(pointer string, ref *CallbackRef) WalkAction {
+			ref.Ref = ri.internalize("callbacks", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+		OnSecurityScheme: func(pointer string, ref *SecuritySchemeRef) WalkAction {
+			ref.Ref = ri.internalize("securitySchemes", ref.Ref, ref.Value)
+			return WalkContinue
+		},
+	})
+}
+
+var componentKinds = []string{
+	"schemas",
+	"parameters",
+	"headers",
+	"requestBodies",
+	"responses",
+	"securitySchemes",
+	"examples",
+	"links",
+	"callbacks",
+}
+
+type refInternalizer struct {
+	doc         *T
+	resolveName func(ref string) string
+
+	// local reference of each component value
+	componentRef map[interface{}]string
+
+	// schemas whose discriminator mapping was internalized
+	visited map[interface{}]struct{}
+}
+
+// components returns the (settable) map of components of the given kind.
+func (ri *refInternalizer) components(kind string) reflect.Value {
+	return componentsOfKind(&ri.doc.Components, kind)
+}
+
+// componentsOfKind returns the (settable) map of components of the given kind
+// (e.g. "schemas").
+func componentsOfKind(c *Components, kind string) reflect.Value {
+	var m interface{}
+	switch kind {
+	case "schemas":
+		m = &c.Schemas
+	case "parameters":
+		m = &c.Parameters
+	case "headers":
+		m = &c.Headers
+	case "requestBodies":
+		m = &c.RequestBodies
+	case "responses":
+		m = &c.Responses
+	case "securitySchemes":
+		m = &c.SecuritySchemes
+	case "examples":
+		m = &c.Examples
+	case "links":
+		m = &c.Links
+	case "callbacks":
+		m = &c.Callbacks
+	default:
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(m).Elem()
+}
+
+// isComponent returns whether ref points to a component of the document holding value.
+func (doc *T) isComponent(ref string, value interface{}) bool {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "#" || parts[1] != "components" {
+		return false
+	}
+	m := componentsOfKind(&doc.Components, parts[2])
+	if !m.IsValid() {
+		return false
+	}
+	c := m.MapIndex(reflect.ValueOf(unescapeRefString(parts[3])))
+	return c.IsValid() && !c.IsNil() && c.Elem().FieldByName("Value").Interface() == value
+}
+
+// internalize returns the local reference to use in place of ref,
+// adding value to the components of the given kind if it is not there yet.
+func (ri *refInternalizer) internalize(kind, ref string, value interface{}) string {
+	if ref == "" || reflect.ValueOf(value).IsNil() {
+		return ref
+	}
+	if local, ok := ri.componentRef[value]; ok {
+		return local
+	}
+	if ri.doc.isComponent(ref, value) {
+		return ref
+	}
+
+	m := ri.components(kind)
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	base := ri.resolveName(ref)
+	if base == "" {
+		base = "Component"
+	}
+	name := base
+	for i := 2; m.MapIndex(reflect.ValueOf(name)).IsValid(); i++ {
+		name = base + strconv.Itoa(i)
+	}
+	component := reflect.New(m.Type().Elem().Elem())
+	component.Elem().FieldByName("Value").Set(reflect.ValueOf(value))
+	m.SetMapIndex(reflect.ValueOf(name), component)
+
+	local := "#/components/" + kind + "/" + name
+	ri.componentRef[value] = local
+	return local
+}
+
+// visit returns whether value has not been seen yet, and marks it seen.
+func (ri *refInternalizer) visit(value interface{}) bool {
+	if _, ok := ri.visited[value]; ok {
+		return false
+	}
+	ri.visited[value] = struct{}{}
+	return true
+}
+
+// schemaRef internalizes the reference of a schema and those of its discriminator mapping,
+// which are the same as the references of its alternatives.
+func (ri *refInternalizer) schemaRef(ref *SchemaRef) {
+	ref.Ref = ri.internalize("schemas", ref.Ref, ref.Value)
+	schema := ref.Value
+	if schema == nil || !ri.visit(schema) {
+		return
+	}
+	if d := schema.Discriminator; d != nil && len(d.Mapping) != 0 {
+		mapping := make(map[string]string)
+		for _, refs := range []SchemaRefs{schema.OneOf, schema.AnyOf} {
+			for _, ref := range refs {
+				if ref != nil && ref.Ref != "" {
+					old := ref.Ref
+					ref.Ref = ri.internalize("schemas", ref.Ref, ref.Value)
+					mapping[old] = ref.Ref
+				}
+			}
+		}
+		for k, v := range d.Mapping {
+			if local, ok := mapping[v]; ok {
+				d.Mapping[k] = local
+			}
+		}
+	}
+}
+
+// sortedMapKeys returns the sorted keys of a map with string keys.
+func sortedMapKeys(m reflect.Value) []string {
+	keys := make([]string, 0, m.Len())
+	for _, k := range m.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
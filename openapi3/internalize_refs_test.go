@@ -0,0 +1,68 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternalizeRefs(t *testing.T) {
+	loader := NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile("testdata/internalizeRefs/openapi.yml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	doc.InternalizeRefs(nil)
+
+	schemas := doc.Components.Schemas
+	require.Len(t, schemas, 6)
+	for name, ref := range schemas {
+		require.Empty(t, ref.Ref, name)
+	}
+	require.Equal(t, "string", schemas["Pet2"].Value.Type)
+	require.Equal(t, "#/components/schemas/Cat", schemas["Pet"].Value.OneOf[0].Ref)
+	require.Equal(t, map[string]string{
+		"cat": "#/components/schemas/Cat",
+		"dog": "#/components/schemas/Dog",
+	}, schemas["Pet"].Value.Discriminator.Mapping)
+	require.Equal(t, "#/components/schemas/Owner", schemas["Cat"].Value.Properties["owner"].Ref)
+	require.Equal(t, "#/components/schemas/Dog", schemas["Dog"].Value.Properties["friend"].Ref)
+	require.Equal(t, "#/components/schemas/Pet", schemas["Owner"].Value.Properties["pets"].Value.Items.Ref)
+	require.Contains(t, doc.Components.Parameters, "Limit")
+	require.Contains(t, doc.Components.Responses, "Error")
+
+	get := doc.Paths["/owners"].Get
+	require.Equal(t, "#/components/parameters/Limit", get.Parameters[0].Ref)
+	properties := get.Responses.Get(200).Value.Content.Get("application/json").Schema.Value.Properties
+	require.Equal(t, "#/components/schemas/Owner", properties["owners"].Value.Items.Ref)
+	require.Equal(t, "#/components/schemas/Pet2", properties["legacyPet"].Ref)
+	require.Equal(t, "#/components/responses/Error", doc.Paths["/pets"].Get.Responses.Default().Ref)
+	require.Equal(t, "#/components/schemas/Error", doc.Components.Responses["Error"].Value.Content.Get("application/json").Schema.Ref)
+
+	// The bundled document loads without access to the other files
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	bundled, err := NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	require.NoError(t, bundled.Validate(loader.Context))
+
+	data, err = yaml.Marshal(doc)
+	require.NoError(t, err)
+	bundled, err = NewLoader().LoadFromData(data)
+	require.NoError(t, err)
+	require.NoError(t, bundled.Validate(loader.Context))
+}
+
+func TestDefaultRefNameResolver(t *testing.T) {
+	for ref, name := range map[string]string{
+		"./schemas/Pet.yml":                      "Pet",
+		"common.yml#/components/schemas/Pet":     "Pet",
+		"common.yml#/components/schemas/a~1b":    "a_b",
+		"https://example.com/api.json#/Pet Kind": "Pet_Kind",
+	} {
+		require.Equal(t, name, DefaultRefNameResolver(ref), ref)
+	}
+}
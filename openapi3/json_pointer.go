@@ -0,0 +1,14 @@
+package openapi3
+
+import "strings"
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// JoinJSONPointer appends tokens to a JSON pointer, escaping them:
+// JoinJSONPointer("/paths", "/pets") is "/paths/~1pets".
+func JoinJSONPointer(pointer string, tokens ...string) string {
+	for _, token := range tokens {
+		pointer += "/" + pointerEscaper.Replace(token)
+	}
+	return pointer
+}
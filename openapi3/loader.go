@@ -35,9 +35,16 @@ type Loader struct {
 
 	Context context.Context
 
+	// IncludeSourceLocations makes the loader record where the elements of the documents
+	// it loads come from, see T.SourceLocation.
+	IncludeSourceLocations bool
+
+	sourceMap *sourceMap
+
 	visitedPathItemRefs map[string]struct{}
 
 	visitedDocuments map[string]*T
+	visitedElements  map[string]interface{}
 
 	visitedExample        map[*Example]struct{}
 	visitedHeader         map[*Header]struct{}
@@ -84,7 +91,8 @@ func (loader *Loader) allowsExternalRefs(ref string) (err error) {
 	return
 }
 
-// loadSingleElementFromURI reads the data from ref and unmarshals to the passed element.
+// loadSingleElementFromURI reads the data from ref and unmarshals to the passed element,
+// a pointer to a pointer that is set to the same value for every ref to the same URI.
 func (loader *Loader) loadSingleElementFromURI(ref string, rootPath *url.URL, element interface{}) (*url.URL, error) {
 	if err := loader.allowsExternalRefs(ref); err != nil {
 		return nil, err
@@ -103,6 +111,13 @@ func (loader *Loader) loadSingleElementFromURI(ref string, rootPath *url.URL, el
 		return nil, fmt.Errorf("could not resolve path: %v", err)
 	}
 
+	uri := resolvedPath.String()
+	target := reflect.ValueOf(element).Elem()
+	if visited, ok := loader.visitedElements[uri]; ok && reflect.TypeOf(visited) == target.Type() {
+		target.Set(reflect.ValueOf(visited))
+		return resolvedPath, nil
+	}
+
 	data, err := loader.readURL(resolvedPath)
 	if err != nil {
 		return nil, err
@@ -110,10 +125,30 @@ func (loader *Loader) loadSingleElementFromURI(ref string, rootPath *url.URL, el
 	if err := yaml.Unmarshal(data, element); err != nil {
 		return nil, err
 	}
+	loader.addSourceLocations(target.Interface(), data, resolvedPath)
 
+	if loader.visitedElements == nil {
+		loader.visitedElements = make(map[string]interface{})
+	}
+	loader.visitedElements[uri] = target.Interface()
 	return resolvedPath, nil
 }
 
+// addSourceLocations records the locations of value, freshly unmarshaled from data,
+// when the loader includes them.
+func (loader *Loader) addSourceLocations(value interface{}, data []byte, location *url.URL) {
+	if !loader.IncludeSourceLocations {
+		return
+	}
+	if loader.sourceMap == nil {
+		loader.sourceMap = &sourceMap{values: make(map[interface{}]*valueSource)}
+	}
+	loader.sourceMap.add(value, data, location)
+	if doc, ok := value.(*T); ok {
+		doc.sourceMap = loader.sourceMap
+	}
+}
+
 func (loader *Loader) readURL(location *url.URL) ([]byte, error) {
 	if f := loader.ReadFromURIFunc; f != nil {
 		return f(loader, location)
@@ -143,6 +178,7 @@ func (loader *Loader) LoadFromData(data []byte) (*T, error) {
 	if err := yaml.Unmarshal(data, doc); err != nil {
 		return nil, err
 	}
+	loader.addSourceLocations(doc, data, nil)
 	if err := loader.ResolveRefsIn(doc, nil); err != nil {
 		return nil, err
 	}
@@ -171,6 +207,7 @@ func (loader *Loader) loadFromDataWithPathInternal(data []byte, location *url.UR
 	if err := yaml.Unmarshal(data, doc); err != nil {
 		return nil, err
 	}
+	loader.addSourceLocations(doc, data, location)
 	if err := loader.ResolveRefsIn(doc, location); err != nil {
 		return nil, err
 	}
@@ -237,6 +274,16 @@ func (loader *Loader) ResolveRefsIn(doc *T, location *url.URL) (err error) {
 		}
 	}
 
+	// Visit all webhooks
+	for name, pathItem := range doc.Webhooks {
+		if pathItem == nil {
+			continue
+		}
+		if err = loader.resolvePathItemRef(doc, "#/webhooks/"+name, pathItem, location); err != nil {
+			return
+		}
+	}
+
 	return
 }
 
@@ -349,13 +396,19 @@ func (loader *Loader) resolveComponent(
 }
 
 func drillIntoField(cursor interface{}, fieldName string) (interface{}, error) {
-	// Special case due to multijson
+	// Special cases due to multijson
 	if s, ok := cursor.(*SchemaRef); ok && fieldName == "additionalProperties" {
 		if ap := s.Value.AdditionalPropertiesAllowed; ap != nil {
 			return *ap, nil
 		}
 		return s.Value.AdditionalProperties, nil
 	}
+	if s, ok := cursor.(*SchemaRef); ok && fieldName == "unevaluatedProperties" {
+		if up := s.Value.UnevaluatedPropertiesAllowed; up != nil {
+			return *up, nil
+		}
+		return s.Value.UnevaluatedProperties, nil
+	}
 
 	switch val := reflect.Indirect(reflect.ValueOf(cursor)); val.Kind() {
 	case reflect.Map:
@@ -417,6 +470,12 @@ func drillIntoField(cursor interface{}, fieldName string) (interface{}, error) {
 
 func (loader *Loader) resolveRef(doc *T, ref string, path *url.URL) (*T, string, *url.URL, error) {
 	if ref != "" && ref[0] == '#' {
+		// Local references found in another document point into that document
+		if path != nil {
+			if pathDoc, ok := loader.visitedDocuments[path.String()]; ok {
+				doc = pathDoc
+			}
+		}
 		return doc, ref, path, nil
 	}
 
@@ -459,11 +518,11 @@ func (loader *Loader) resolveHeaderRef(doc *T, component *HeaderRef, documentPat
 	}
 	if ref := component.Ref; ref != "" {
 		if isSingleRefElement(ref) {
-			var header Header
+			var header *Header
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &header); err != nil {
 				return err
 			}
-			component.Value = &header
+			component.Value = header
 		} else {
 			var resolved HeaderRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -506,11 +565,11 @@ func (loader *Loader) resolveParameterRef(doc *T, component *ParameterRef, docum
 	ref := component.Ref
 	if ref != "" {
 		if isSingleRefElement(ref) {
-			var param Parameter
+			var param *Parameter
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &param); err != nil {
 				return err
 			}
-			component.Value = &param
+			component.Value = param
 		} else {
 			var resolved ParameterRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -562,11 +621,11 @@ func (loader *Loader) resolveRequestBodyRef(doc *T, component *RequestBodyRef, d
 	}
 	if ref := component.Ref; ref != "" {
 		if isSingleRefElement(ref) {
-			var requestBody RequestBody
+			var requestBody *RequestBody
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &requestBody); err != nil {
 				return err
 			}
-			component.Value = &requestBody
+			component.Value = requestBody
 		} else {
 			var resolved RequestBodyRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -617,11 +676,11 @@ func (loader *Loader) resolveResponseRef(doc *T, component *ResponseRef, documen
 	ref := component.Ref
 	if ref != "" {
 		if isSingleRefElement(ref) {
-			var resp Response
+			var resp *Response
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &resp); err != nil {
 				return err
 			}
-			component.Value = &resp
+			component.Value = resp
 		} else {
 			var resolved ResponseRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -686,11 +745,11 @@ func (loader *Loader) resolveSchemaRef(doc *T, component *SchemaRef, documentPat
 	ref := component.Ref
 	if ref != "" {
 		if isSingleRefElement(ref) {
-			var schema Schema
+			var schema *Schema
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &schema); err != nil {
 				return err
 			}
-			component.Value = &schema
+			component.Value = schema
 		} else {
 			var resolved SchemaRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -744,6 +803,29 @@ func (loader *Loader) resolveSchemaRef(doc *T, component *SchemaRef, documentPat
 			return err
 		}
 	}
+	for _, v := range []*SchemaRef{value.If, value.Then, value.Else, value.UnevaluatedProperties} {
+		if v == nil {
+			continue
+		}
+		if err := loader.resolveSchemaRef(doc, v, documentPath); err != nil {
+			return err
+		}
+	}
+	for _, v := range value.PrefixItems {
+		if err := loader.resolveSchemaRef(doc, v, documentPath); err != nil {
+			return err
+		}
+	}
+	for _, v := range value.DependentSchemas {
+		if err := loader.resolveSchemaRef(doc, v, documentPath); err != nil {
+			return err
+		}
+	}
+	for _, v := range value.Defs {
+		if err := loader.resolveSchemaRef(doc, v, documentPath); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -763,11 +845,11 @@ func (loader *Loader) resolveSecuritySchemeRef(doc *T, component *SecurityScheme
 	}
 	if ref := component.Ref; ref != "" {
 		if isSingleRefElement(ref) {
-			var scheme SecurityScheme
+			var scheme *SecurityScheme
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &scheme); err != nil {
 				return err
 			}
-			component.Value = &scheme
+			component.Value = scheme
 		} else {
 			var resolved SecuritySchemeRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -799,11 +881,11 @@ func (loader *Loader) resolveExampleRef(doc *T, component *ExampleRef, documentP
 	}
 	if ref := component.Ref; ref != "" {
 		if isSingleRefElement(ref) {
-			var example Example
+			var example *Example
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &example); err != nil {
 				return err
 			}
-			component.Value = &example
+			component.Value = example
 		} else {
 			var resolved ExampleRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -826,11 +908,11 @@ func (loader *Loader) resolveCallbackRef(doc *T, component *CallbackRef, documen
 	}
 	if ref := component.Ref; ref != "" {
 		if isSingleRefElement(ref) {
-			var resolved Callback
+			var resolved *Callback
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &resolved); err != nil {
 				return err
 			}
-			component.Value = &resolved
+			component.Value = resolved
 		} else {
 			var resolved CallbackRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -867,11 +949,11 @@ func (loader *Loader) resolveCallbackRef(doc *T, component *CallbackRef, documen
 			ref := pathItem.Ref
 			if ref != "" {
 				if isSingleRefElement(ref) {
-					var p PathItem
+					var p *PathItem
 					if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &p); err != nil {
 						return err
 					}
-					*pathItem = p
+					*pathItem = *p
 				} else {
 					if doc, ref, documentPath, err = loader.resolveRef(doc, ref, documentPath); err != nil {
 						return
@@ -923,11 +1005,11 @@ func (loader *Loader) resolveLinkRef(doc *T, component *LinkRef, documentPath *u
 	}
 	if ref := component.Ref; ref != "" {
 		if isSingleRefElement(ref) {
-			var link Link
+			var link *Link
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &link); err != nil {
 				return err
 			}
-			component.Value = &link
+			component.Value = link
 		} else {
 			var resolved LinkRef
 			componentPath, err := loader.resolveComponent(doc, ref, documentPath, &resolved)
@@ -960,11 +1042,11 @@ func (loader *Loader) resolvePathItemRef(doc *T, entrypoint string, pathItem *Pa
 	ref := pathItem.Ref
 	if ref != "" {
 		if isSingleRefElement(ref) {
-			var p PathItem
+			var p *PathItem
 			if documentPath, err = loader.loadSingleElementFromURI(ref, documentPath, &p); err != nil {
 				return err
 			}
-			*pathItem = p
+			*pathItem = *p
 		} else {
 			if doc, ref, documentPath, err = loader.resolveRef(doc, ref, documentPath); err != nil {
 				return
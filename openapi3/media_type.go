@@ -72,7 +72,7 @@ func (value *MediaType) Validate(ctx context.Context) error {
 		return nil
 	}
 	if schema := value.Schema; schema != nil {
-		if err := schema.Validate(ctx); err != nil {
+		if err := validateAt(ctx, schema.Validate, "schema"); err != nil {
 			return err
 		}
 	}
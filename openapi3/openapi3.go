@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
 )
@@ -14,11 +15,22 @@ type T struct {
 	OpenAPI      string               `json:"openapi" yaml:"openapi"` // Required
 	Components   Components           `json:"components,omitempty" yaml:"components,omitempty"`
 	Info         *Info                `json:"info" yaml:"info"`   // Required
-	Paths        Paths                `json:"paths" yaml:"paths"` // Required
+	Paths        Paths                `json:"paths" yaml:"paths"` // Required (in OpenAPI 3.0)
 	Security     SecurityRequirements `json:"security,omitempty" yaml:"security,omitempty"`
 	Servers      Servers              `json:"servers,omitempty" yaml:"servers,omitempty"`
 	Tags         Tags                 `json:"tags,omitempty" yaml:"tags,omitempty"`
 	ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+
+	// OpenAPI 3.1
+	Webhooks          Webhooks `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	JSONSchemaDialect string   `json:"jsonSchemaDialect,omitempty" yaml:"jsonSchemaDialect,omitempty"`
+
+	sourceMap *sourceMap
+}
+
+// IsOpenAPI3_1 returns whether the document declares an OpenAPI 3.1.x version.
+func (doc *T) IsOpenAPI3_1() bool {
+	return doc.OpenAPI == "3.1" || strings.HasPrefix(doc.OpenAPI, "3.1.")
 }
 
 func (doc *T) MarshalJSON() ([]byte, error) {
@@ -47,21 +59,69 @@ func (doc *T) AddServer(server *Server) {
 	doc.Servers = append(doc.Servers, server)
 }
 
-func (value *T) Validate(ctx context.Context) error {
-	if value.OpenAPI == "" {
-		return errors.New("value of openapi must be a non-empty string")
+// Validate returns an error if T does not comply with the OpenAPI spec.
+// Validation options can be provided to modify the validation behavior.
+func (value *T) Validate(ctx context.Context, opts ...ValidationOption) error {
+	settings := newValidationSettings(opts...)
+
+	var collector *issueCollector
+	if settings.collectAllErrors {
+		collector = &issueCollector{doc: value}
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx = context.WithValue(ctx, validationIssuesKey{}, collector)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, openAPI3_1Key{}, value.IsOpenAPI3_1())
+
+	if err := value.validate(ctx); err != nil {
+		return err
+	}
+
+	if settings.examplesValidation {
+		if err := validateExamples(value); err != nil {
+			if collector == nil {
+				return err
+			}
+			for _, e := range err.(MultiError) {
+				e := e.(*ExampleError)
+				collector.add(e.Pointer, e.reason())
+			}
+		}
+	}
+
+	if collector != nil && len(collector.issues) != 0 {
+		return collector.issues
+	}
+	return nil
+}
+
+func (value *T) validate(ctx context.Context) error {
+	if err := validateAt(ctx, func(ctx context.Context) error {
+		if value.OpenAPI == "" {
+			return errors.New("value of openapi must be a non-empty string")
+		}
+		return nil
+	}, "openapi"); err != nil {
+		return err
 	}
 
 	// NOTE: only mention info/components/paths/... key in this func's errors.
 
-	{
+	if err := validateAt(ctx, func(ctx context.Context) error {
 		wrap := func(e error) error { return fmt.Errorf("invalid components: %v", e) }
 		if err := value.Components.Validate(ctx); err != nil {
 			return wrap(err)
 		}
+		return nil
+	}, "components"); err != nil {
+		return err
 	}
 
-	{
+	if err := validateAt(ctx, func(ctx context.Context) error {
 		wrap := func(e error) error { return fmt.Errorf("invalid info: %v", e) }
 		if v := value.Info; v != nil {
 			if err := v.Validate(ctx); err != nil {
@@ -70,36 +130,68 @@ func (value *T) Validate(ctx context.Context) error {
 		} else {
 			return wrap(errors.New("must be an object"))
 		}
+		return nil
+	}, "info"); err != nil {
+		return err
 	}
 
-	{
+	if err := validateAt(ctx, func(ctx context.Context) error {
 		wrap := func(e error) error { return fmt.Errorf("invalid paths: %v", e) }
 		if v := value.Paths; v != nil {
 			if err := v.Validate(ctx); err != nil {
 				return wrap(err)
 			}
-		} else {
+		} else if !value.IsOpenAPI3_1() {
 			return wrap(errors.New("must be an object"))
 		}
+		return nil
+	}, "paths"); err != nil {
+		return err
 	}
 
-	{
+	if err := validateAt(ctx, func(ctx context.Context) error {
+		wrap := func(e error) error { return fmt.Errorf("invalid webhooks: %v", e) }
+		if v := value.Webhooks; v != nil {
+			if !value.IsOpenAPI3_1() {
+				return wrap(fmt.Errorf("not supported by OpenAPI %s", value.OpenAPI))
+			}
+			if err := v.Validate(ctx); err != nil {
+				return wrap(err)
+			}
+		}
+		return nil
+	}, "webhooks"); err != nil {
+		return err
+	}
+
+	if err := validateAt(ctx, func(ctx context.Context) error {
+		if value.IsOpenAPI3_1() && value.Paths == nil && value.Webhooks == nil && value.Components.isEmpty() {
+			return errors.New("at least one of paths, webhooks or components must be present")
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := validateAt(ctx, func(ctx context.Context) error {
 		wrap := func(e error) error { return fmt.Errorf("invalid security: %v", e) }
 		if v := value.Security; v != nil {
 			if err := v.Validate(ctx); err != nil {
 				return wrap(err)
 			}
 		}
+		return nil
+	}, "security"); err != nil {
+		return err
 	}
 
-	{
+	return validateAt(ctx, func(ctx context.Context) error {
 		wrap := func(e error) error { return fmt.Errorf("invalid servers: %v", e) }
 		if v := value.Servers; v != nil {
 			if err := v.Validate(ctx); err != nil {
 				return wrap(err)
 			}
 		}
-	}
-
-	return nil
+		return nil
+	}, "servers")
 }
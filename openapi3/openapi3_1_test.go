@@ -0,0 +1,184 @@
+package openapi3
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI3_1(t *testing.T) {
+	spec := []byte(`
+openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+jsonSchemaDialect: https://json-schema.org/draft/2020-12/schema
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name, age]
+      properties:
+        name:
+          type: [string, "null"]
+        age:
+          $ref: '#/components/schemas/Pet/$defs/Age'
+      $defs:
+        Age:
+          type: integer
+          exclusiveMinimum: 0
+`)
+
+	loader := NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+	require.True(t, doc.IsOpenAPI3_1())
+	require.Nil(t, doc.Paths)
+	require.Equal(t, "https://json-schema.org/draft/2020-12/schema", doc.JSONSchemaDialect)
+	require.Contains(t, doc.Webhooks, "newPet")
+
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+
+	schema := doc.Webhooks["newPet"].Post.RequestBody.Value.Content.Get("application/json").Schema.Value
+	require.NotNil(t, schema)
+	require.Equal(t, []string{"string", "null"}, schema.Properties["name"].Value.Types)
+	age := schema.Properties["age"].Value
+	require.NotNil(t, age)
+	require.NotNil(t, age.ExclusiveMinValue)
+	require.Equal(t, 0.0, *age.ExclusiveMinValue)
+
+	err = schema.VisitJSON(map[string]interface{}{"name": nil, "age": 3.0})
+	require.NoError(t, err)
+	err = schema.VisitJSON(map[string]interface{}{"name": "Tom", "age": 0.0})
+	require.Error(t, err)
+	err = schema.VisitJSON(map[string]interface{}{"name": 42.0, "age": 3.0})
+	require.Error(t, err)
+
+	data, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"webhooks"`)
+	require.Contains(t, string(data), `"type":["string","null"]`)
+	require.Contains(t, string(data), `"exclusiveMinimum":0`)
+}
+
+func TestOpenAPI3_1ArrayWithoutItems(t *testing.T) {
+	loader := NewLoader()
+	doc, err := loader.LoadFromFile("testdata/openapi3_1-array.yml")
+	require.NoError(t, err)
+	tags := doc.Components.Schemas["Tags"].Value
+	require.Equal(t, "array", tags.Type)
+	require.Empty(t, tags.Types)
+
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+
+	doc.OpenAPI = "3.0.3"
+	err = doc.Validate(loader.Context)
+	require.EqualError(t, err, `invalid components: when schema type is 'array', schema 'items' must be non-null`)
+}
+
+func TestOpenAPI3_0RejectsWebhooks(t *testing.T) {
+	doc := &T{
+		OpenAPI: "3.0.3",
+		Info:    &Info{Title: "Pets", Version: "1.0.0"},
+		Paths:   Paths{},
+		Webhooks: Webhooks{
+			"newPet": &PathItem{},
+		},
+	}
+	err := doc.Validate(context.Background())
+	require.Error(t, err)
+
+	doc.OpenAPI = "3.1.0"
+	err = doc.Validate(context.Background())
+	require.NoError(t, err)
+}
+
+func TestSchemaConst(t *testing.T) {
+	schema := NewSchema().WithConst(1)
+	require.NoError(t, schema.VisitJSON(1.0))
+	require.Error(t, schema.VisitJSON(2.0))
+
+	schema = NewSchema().WithConst(map[string]interface{}{"ids": []interface{}{1, 2}})
+	require.NoError(t, schema.VisitJSON(map[string]interface{}{"ids": []interface{}{1.0, 2.0}}))
+
+	loader := NewLoader()
+	doc, err := loader.LoadFromData([]byte(`
+openapi: 3.1.0
+info:
+  title: Const
+  version: 1.0.0
+components:
+  schemas:
+    Nothing:
+      const: null
+`))
+	require.NoError(t, err)
+	nothing := doc.Components.Schemas["Nothing"].Value
+	require.NoError(t, nothing.VisitJSON(nil))
+	require.Error(t, nothing.VisitJSON("something"))
+
+	data, err := nothing.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"const":null}`, string(data))
+
+	dereferenced, err := doc.Dereference()
+	require.NoError(t, err)
+	require.Error(t, dereferenced.Components.Schemas["Nothing"].Value.VisitJSON("something"))
+}
+
+func TestOpenAPI3_0RejectsOpenAPI3_1Keywords(t *testing.T) {
+	for keyword, schema := range map[string]string{
+		"type":             `{type: [string, "null"]}`,
+		"exclusiveMinimum": `{type: integer, exclusiveMinimum: 0}`,
+		"const":            `{const: 1}`,
+		"prefixItems":      `{type: array, items: {}, prefixItems: [{type: string}]}`,
+		"$defs":            `{$defs: {Age: {type: integer}}}`,
+		"if":               `{if: {type: string}}`,
+		"dependentSchemas": `{dependentSchemas: {name: {required: [age]}}}`,
+	} {
+		t.Run(keyword, func(t *testing.T) {
+			spec := `
+openapi: %s
+info:
+  title: Keywords
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Value: ` + schema + `
+`
+			loader := NewLoader()
+			doc, err := loader.LoadFromData([]byte(fmt.Sprintf(spec, "3.0.3")))
+			require.NoError(t, err)
+			err = doc.Validate(loader.Context)
+			require.EqualError(t, err, fmt.Sprintf(`invalid components: schema keyword %q requires OpenAPI 3.1`, keyword))
+
+			doc, err = loader.LoadFromData([]byte(fmt.Sprintf(spec, "3.1.0")))
+			require.NoError(t, err)
+			err = doc.Validate(loader.Context)
+			require.NoError(t, err)
+		})
+	}
+
+	// The boolean exclusive bounds of OpenAPI 3.0 are not taken for numeric ones
+	schema := NewSchema()
+	err := schema.UnmarshalJSON([]byte(`{"type":"integer","exclusiveMinimum":true}`))
+	require.NoError(t, err)
+	require.True(t, schema.ExclusiveMin)
+	require.Nil(t, schema.ExclusiveMinValue)
+}
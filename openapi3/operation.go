@@ -122,17 +122,17 @@ func (operation *Operation) AddResponse(status int, response *Response) {
 
 func (value *Operation) Validate(ctx context.Context) error {
 	if v := value.Parameters; v != nil {
-		if err := v.Validate(ctx); err != nil {
+		if err := validateAt(ctx, v.Validate, "parameters"); err != nil {
 			return err
 		}
 	}
 	if v := value.RequestBody; v != nil {
-		if err := v.Validate(ctx); err != nil {
+		if err := validateAt(ctx, v.Validate, "requestBody"); err != nil {
 			return err
 		}
 	}
 	if v := value.Responses; v != nil {
-		if err := v.Validate(ctx); err != nil {
+		if err := validateAt(ctx, v.Validate, "responses"); err != nil {
 			return err
 		}
 	} else {
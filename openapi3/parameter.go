@@ -66,16 +66,17 @@ func (parameters Parameters) GetByInAndName(in string, name string) *Parameter {
 
 func (value Parameters) Validate(ctx context.Context) error {
 	dupes := make(map[string]struct{})
-	for _, item := range value {
-		if v := item.Value; v != nil {
-			key := v.In + ":" + v.Name
-			if _, ok := dupes[key]; ok {
-				return fmt.Errorf("more than one %q parameter has name %q", v.In, v.Name)
+	for i, item := range value {
+		if err := validateAt(ctx, func(ctx context.Context) error {
+			if v := item.Value; v != nil {
+				key := v.In + ":" + v.Name
+				if _, ok := dupes[key]; ok {
+					return fmt.Errorf("more than one %q parameter has name %q", v.In, v.Name)
+				}
+				dupes[key] = struct{}{}
 			}
-			dupes[key] = struct{}{}
-		}
-
-		if err := item.Validate(ctx); err != nil {
+			return item.Validate(ctx)
+		}, strconv.Itoa(i)); err != nil {
 			return err
 		}
 	}
@@ -291,13 +292,13 @@ func (value *Parameter) Validate(ctx context.Context) error {
 		return fmt.Errorf("parameter %q schema is invalid: %v", value.Name, e)
 	}
 	if schema := value.Schema; schema != nil {
-		if err := schema.Validate(ctx); err != nil {
+		if err := validateAt(ctx, schema.Validate, "schema"); err != nil {
 			return fmt.Errorf("parameter %q schema is invalid: %v", value.Name, err)
 		}
 	}
 
 	if content := value.Content; content != nil {
-		if err := content.Validate(ctx); err != nil {
+		if err := validateAt(ctx, content.Validate, "content"); err != nil {
 			return fmt.Errorf("parameter %q content is invalid: %v", value.Name, err)
 		}
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
 )
@@ -117,8 +119,9 @@ func (pathItem *PathItem) SetOperation(method string, operation *Operation) {
 }
 
 func (value *PathItem) Validate(ctx context.Context) error {
-	for _, operation := range value.Operations() {
-		if err := operation.Validate(ctx); err != nil {
+	operations := value.Operations()
+	for _, method := range sortedMapKeys(reflect.ValueOf(operations)) {
+		if err := validateAt(ctx, operations[method].Validate, strings.ToLower(method)); err != nil {
 			return err
 		}
 	}
@@ -3,6 +3,7 @@ package openapi3
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -11,31 +12,45 @@ type Paths map[string]*PathItem
 
 func (value Paths) Validate(ctx context.Context) error {
 	normalizedPaths := make(map[string]string)
-	for path, pathItem := range value {
-		if path == "" || path[0] != '/' {
-			return fmt.Errorf("path %q does not start with a forward slash (/)", path)
+	for _, path := range sortedMapKeys(reflect.ValueOf(value)) {
+		if err := validateAt(ctx, func(ctx context.Context) error {
+			return value.validatePath(ctx, path, normalizedPaths)
+		}, path); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		if pathItem == nil {
-			value[path] = &PathItem{}
-			pathItem = value[path]
-		}
+func (value Paths) validatePath(ctx context.Context, path string, normalizedPaths map[string]string) error {
+	pathItem := value[path]
+	if path == "" || path[0] != '/' {
+		return fmt.Errorf("path %q does not start with a forward slash (/)", path)
+	}
 
-		normalizedPath, pathParamsCount := normalizeTemplatedPath(path)
-		if oldPath, ok := normalizedPaths[normalizedPath]; ok {
-			return fmt.Errorf("conflicting paths %q and %q", path, oldPath)
-		}
-		normalizedPaths[path] = path
+	if pathItem == nil {
+		value[path] = &PathItem{}
+		pathItem = value[path]
+	}
 
-		var globalCount uint
-		for _, parameterRef := range pathItem.Parameters {
-			if parameterRef != nil {
-				if parameter := parameterRef.Value; parameter != nil && parameter.In == ParameterInPath {
-					globalCount++
-				}
+	normalizedPath, pathParamsCount := normalizeTemplatedPath(path)
+	if oldPath, ok := normalizedPaths[normalizedPath]; ok {
+		return fmt.Errorf("conflicting paths %q and %q", path, oldPath)
+	}
+	normalizedPaths[path] = path
+
+	var globalCount uint
+	for _, parameterRef := range pathItem.Parameters {
+		if parameterRef != nil {
+			if parameter := parameterRef.Value; parameter != nil && parameter.In == ParameterInPath {
+				globalCount++
 			}
 		}
-		for method, operation := range pathItem.Operations() {
+	}
+	operations := pathItem.Operations()
+	for _, method := range sortedMapKeys(reflect.ValueOf(operations)) {
+		operation := operations[method]
+		if err := validateAt(ctx, func(ctx context.Context) error {
 			var count uint
 			for _, parameterRef := range operation.Parameters {
 				if parameterRef != nil {
@@ -47,12 +62,15 @@ func (value Paths) Validate(ctx context.Context) error {
 			if count+globalCount != pathParamsCount {
 				return fmt.Errorf("operation %s %s must define exactly all path parameters", method, path)
 			}
-		}
-
-		if err := pathItem.Validate(ctx); err != nil {
+			return nil
+		}, strings.ToLower(method)); err != nil {
 			return err
 		}
 	}
+
+	if err := pathItem.Validate(ctx); err != nil {
+		return err
+	}
 	return nil
 }
 
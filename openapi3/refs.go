@@ -28,6 +28,9 @@ func (value *CallbackRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *CallbackRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -59,6 +62,9 @@ func (value *ExampleRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *ExampleRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -90,6 +96,9 @@ func (value *HeaderRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *HeaderRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -119,6 +128,9 @@ func (value *LinkRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *LinkRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -141,6 +153,9 @@ func (value *ParameterRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *ParameterRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -172,6 +187,9 @@ func (value *ResponseRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *ResponseRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -203,6 +221,9 @@ func (value *RequestBodyRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *RequestBodyRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -241,6 +262,9 @@ func (value *SchemaRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *SchemaRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
@@ -272,6 +296,9 @@ func (value *SecuritySchemeRef) UnmarshalJSON(data []byte) error {
 }
 
 func (value *SecuritySchemeRef) Validate(ctx context.Context) error {
+	if validatedElsewhere(ctx, value.Ref, value.Value) {
+		return nil
+	}
 	if v := value.Value; v != nil {
 		return v.Validate(ctx)
 	}
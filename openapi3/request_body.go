@@ -99,7 +99,7 @@ func (requestBody *RequestBody) UnmarshalJSON(data []byte) error {
 
 func (value *RequestBody) Validate(ctx context.Context) error {
 	if v := value.Content; v != nil {
-		if err := v.Validate(ctx); err != nil {
+		if err := validateAt(ctx, v.Validate, "content"); err != nil {
 			return err
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
@@ -33,8 +34,8 @@ func (value Responses) Validate(ctx context.Context) error {
 	if len(value) == 0 {
 		return errors.New("the responses object MUST contain at least one response code")
 	}
-	for _, v := range value {
-		if err := v.Validate(ctx); err != nil {
+	for _, status := range sortedMapKeys(reflect.ValueOf(value)) {
+		if err := validateAt(ctx, value[status].Validate, status); err != nil {
 			return err
 		}
 	}
@@ -100,7 +101,7 @@ func (value *Response) Validate(ctx context.Context) error {
 	}
 
 	if content := value.Content; content != nil {
-		if err := content.Validate(ctx); err != nil {
+		if err := validateAt(ctx, content.Validate, "content"); err != nil {
 			return err
 		}
 	}
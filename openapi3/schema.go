@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"unicode/utf16"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
@@ -100,32 +103,42 @@ type Schema struct {
 	AnyOf        SchemaRefs    `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
 	AllOf        SchemaRefs    `json:"allOf,omitempty" yaml:"allOf,omitempty"`
 	Not          *SchemaRef    `json:"not,omitempty" yaml:"not,omitempty"`
-	Type         string        `json:"type,omitempty" yaml:"type,omitempty"`
+	If           *SchemaRef    `json:"if,omitempty" yaml:"if,omitempty"`
+	Then         *SchemaRef    `json:"then,omitempty" yaml:"then,omitempty"`
+	Else         *SchemaRef    `json:"else,omitempty" yaml:"else,omitempty"`
+	Type         string        `multijson:"type,omitempty" json:"-" yaml:"-"` // In this order...
+	Types        []string      `multijson:"type,omitempty" json:"-" yaml:"-"` // ...for multijson (OpenAPI 3.1 lists of types)
 	Title        string        `json:"title,omitempty" yaml:"title,omitempty"`
 	Format       string        `json:"format,omitempty" yaml:"format,omitempty"`
 	Description  string        `json:"description,omitempty" yaml:"description,omitempty"`
 	Enum         []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Const        interface{}   `json:"const,omitempty" yaml:"const,omitempty"` // See WithConst for null
+	constSet     bool          // Tells Const: null apart from no const
 	Default      interface{}   `json:"default,omitempty" yaml:"default,omitempty"`
 	Example      interface{}   `json:"example,omitempty" yaml:"example,omitempty"`
 	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	Defs         Schemas       `json:"$defs,omitempty" yaml:"$defs,omitempty"`
 
 	// Array-related, here for struct compactness
 	UniqueItems bool `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
 	// Number-related, here for struct compactness
-	ExclusiveMin bool `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
-	ExclusiveMax bool `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	ExclusiveMin bool `multijson:"exclusiveMinimum,omitempty" json:"-" yaml:"-"` // In this order...
+	ExclusiveMax bool `multijson:"exclusiveMaximum,omitempty" json:"-" yaml:"-"` // ...for multijson
 	// Properties
-	Nullable        bool        `json:"nullable,omitempty" yaml:"nullable,omitempty"`
-	ReadOnly        bool        `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
-	WriteOnly       bool        `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
-	AllowEmptyValue bool        `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
-	XML             interface{} `json:"xml,omitempty" yaml:"xml,omitempty"`
-	Deprecated      bool        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Nullable        bool `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	ReadOnly        bool `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	WriteOnly       bool `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
+	AllowEmptyValue bool `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	XML             *XML `json:"xml,omitempty" yaml:"xml,omitempty"`
+	Deprecated      bool `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
 
 	// Number
 	Min        *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
 	Max        *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
 	MultipleOf *float64 `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	// OpenAPI 3.1 numeric exclusive bounds, sharing their keywords with ExclusiveMin and ExclusiveMax
+	ExclusiveMinValue *float64 `multijson:"exclusiveMinimum,omitempty" json:"-" yaml:"-"`
+	ExclusiveMaxValue *float64 `multijson:"exclusiveMaximum,omitempty" json:"-" yaml:"-"`
 
 	// String
 	MinLength       uint64  `json:"minLength,omitempty" yaml:"minLength,omitempty"`
@@ -134,9 +147,10 @@ type Schema struct {
 	compiledPattern *regexp.Regexp
 
 	// Array
-	MinItems uint64     `json:"minItems,omitempty" yaml:"minItems,omitempty"`
-	MaxItems *uint64    `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
-	Items    *SchemaRef `json:"items,omitempty" yaml:"items,omitempty"`
+	MinItems    uint64     `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems    *uint64    `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Items       *SchemaRef `json:"items,omitempty" yaml:"items,omitempty"`
+	PrefixItems SchemaRefs `json:"prefixItems,omitempty" yaml:"prefixItems,omitempty"`
 
 	// Object
 	Required                    []string       `json:"required,omitempty" yaml:"required,omitempty"`
@@ -146,6 +160,10 @@ type Schema struct {
 	AdditionalPropertiesAllowed *bool          `multijson:"additionalProperties,omitempty" json:"-" yaml:"-"` // In this order...
 	AdditionalProperties        *SchemaRef     `multijson:"additionalProperties,omitempty" json:"-" yaml:"-"` // ...for multijson
 	Discriminator               *Discriminator `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`
+
+	DependentSchemas             Schemas    `json:"dependentSchemas,omitempty" yaml:"dependentSchemas,omitempty"`
+	UnevaluatedPropertiesAllowed *bool      `multijson:"unevaluatedProperties,omitempty" json:"-" yaml:"-"` // In this order...
+	UnevaluatedProperties        *SchemaRef `multijson:"unevaluatedProperties,omitempty" json:"-" yaml:"-"` // ...for multijson
 }
 
 var _ jsonpointer.JSONPointable = (*Schema)(nil)
@@ -155,11 +173,29 @@ func NewSchema() *Schema {
 }
 
 func (schema *Schema) MarshalJSON() ([]byte, error) {
-	return jsoninfo.MarshalStrictStruct(schema)
+	data, err := jsoninfo.MarshalStrictStruct(schema)
+	if err != nil || !schema.constSet || schema.Const != nil {
+		return data, err
+	}
+	// Const is omitted when null
+	if len(data) > 2 {
+		return append(data[:len(data)-1], `,"const":null}`...), nil
+	}
+	return []byte(`{"const":null}`), nil
 }
 
 func (schema *Schema) UnmarshalJSON(data []byte) error {
-	return jsoninfo.UnmarshalStrictStruct(data, schema)
+	if err := jsoninfo.UnmarshalStrictStruct(data, schema); err != nil {
+		return err
+	}
+	if schema.Const == nil && bytes.Contains(data, []byte(`"const"`)) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		_, schema.constSet = fields["const"]
+	}
+	return nil
 }
 
 func (schema Schema) JSONLookup(token string) (interface{}, error) {
@@ -178,6 +214,24 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 			}
 			return schema.Not.Value, nil
 		}
+	case "if", "then", "else":
+		ref := map[string]*SchemaRef{"if": schema.If, "then": schema.Then, "else": schema.Else}[token]
+		if ref != nil {
+			if ref.Ref != "" {
+				return &Ref{Ref: ref.Ref}, nil
+			}
+			return ref.Value, nil
+		}
+	case "unevaluatedProperties":
+		if schema.UnevaluatedProperties != nil {
+			if schema.UnevaluatedProperties.Ref != "" {
+				return &Ref{Ref: schema.UnevaluatedProperties.Ref}, nil
+			}
+			return schema.UnevaluatedProperties.Value, nil
+		}
+		if schema.UnevaluatedPropertiesAllowed != nil {
+			return *schema.UnevaluatedPropertiesAllowed, nil
+		}
 	case "items":
 		if schema.Items != nil {
 			if schema.Items.Ref != "" {
@@ -192,6 +246,9 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 	case "allOf":
 		return schema.AllOf, nil
 	case "type":
+		if len(schema.Types) != 0 {
+			return schema.Types, nil
+		}
 		return schema.Type, nil
 	case "title":
 		return schema.Title, nil
@@ -201,6 +258,10 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 		return schema.Description, nil
 	case "enum":
 		return schema.Enum, nil
+	case "const":
+		return schema.Const, nil
+	case "$defs":
+		return schema.Defs, nil
 	case "default":
 		return schema.Default, nil
 	case "example":
@@ -215,6 +276,16 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 		return schema.ExclusiveMin, nil
 	case "exclusiveMax":
 		return schema.ExclusiveMax, nil
+	case "exclusiveMinimum":
+		if schema.ExclusiveMinValue != nil {
+			return *schema.ExclusiveMinValue, nil
+		}
+		return schema.ExclusiveMin, nil
+	case "exclusiveMaximum":
+		if schema.ExclusiveMaxValue != nil {
+			return *schema.ExclusiveMaxValue, nil
+		}
+		return schema.ExclusiveMax, nil
 	case "nullable":
 		return schema.Nullable, nil
 	case "readOnly":
@@ -243,6 +314,8 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 		return schema.MinItems, nil
 	case "maxItems":
 		return schema.MaxItems, nil
+	case "prefixItems":
+		return schema.PrefixItems, nil
 	case "required":
 		return schema.Required, nil
 	case "properties":
@@ -253,6 +326,8 @@ func (schema Schema) JSONLookup(token string) (interface{}, error) {
 		return schema.MaxProps, nil
 	case "discriminator":
 		return schema.Discriminator, nil
+	case "dependentSchemas":
+		return schema.DependentSchemas, nil
 	}
 
 	v, _, err := jsonpointer.GetForToken(schema.ExtensionProps, token)
@@ -367,6 +442,13 @@ func NewObjectSchema() *Schema {
 	}
 }
 
+// WithTypes sets an OpenAPI 3.1 list of types, e.g. "string" and "null".
+func (schema *Schema) WithTypes(types ...string) *Schema {
+	schema.Type = ""
+	schema.Types = types
+	return schema
+}
+
 func (schema *Schema) WithNullable() *Schema {
 	schema.Nullable = true
 	return schema
@@ -391,11 +473,35 @@ func (schema *Schema) WithExclusiveMax(value bool) *Schema {
 	return schema
 }
 
+// WithExclusiveMinValue sets an OpenAPI 3.1 numeric exclusive lower bound.
+func (schema *Schema) WithExclusiveMinValue(value float64) *Schema {
+	schema.ExclusiveMinValue = &value
+	return schema
+}
+
+// WithExclusiveMaxValue sets an OpenAPI 3.1 numeric exclusive upper bound.
+func (schema *Schema) WithExclusiveMaxValue(value float64) *Schema {
+	schema.ExclusiveMaxValue = &value
+	return schema
+}
+
 func (schema *Schema) WithEnum(values ...interface{}) *Schema {
 	schema.Enum = values
 	return schema
 }
 
+// WithConst sets the value the schema allows, which can be nil for null
+// unlike assigning Const.
+func (schema *Schema) WithConst(value interface{}) *Schema {
+	schema.Const, schema.constSet = value, true
+	return schema
+}
+
+// HasConst tells whether the schema has a const, null included.
+func (schema *Schema) HasConst() bool {
+	return schema.constSet || schema.Const != nil
+}
+
 func (schema *Schema) WithDefault(defaultValue interface{}) *Schema {
 	schema.Default = defaultValue
 	return schema
@@ -533,10 +639,11 @@ func (schema *Schema) WithAdditionalProperties(v *Schema) *Schema {
 }
 
 func (schema *Schema) IsEmpty() bool {
-	if schema.Type != "" || schema.Format != "" || len(schema.Enum) != 0 ||
+	if schema.Type != "" || len(schema.Types) != 0 || schema.Format != "" || len(schema.Enum) != 0 || schema.HasConst() ||
 		schema.UniqueItems || schema.ExclusiveMin || schema.ExclusiveMax ||
 		schema.Nullable || schema.ReadOnly || schema.WriteOnly || schema.AllowEmptyValue ||
 		schema.Min != nil || schema.Max != nil || schema.MultipleOf != nil ||
+		schema.ExclusiveMinValue != nil || schema.ExclusiveMaxValue != nil ||
 		schema.MinLength != 0 || schema.MaxLength != nil || schema.Pattern != "" ||
 		schema.MinItems != 0 || schema.MaxItems != nil ||
 		len(schema.Required) != 0 ||
@@ -546,6 +653,24 @@ func (schema *Schema) IsEmpty() bool {
 	if n := schema.Not; n != nil && !n.Value.IsEmpty() {
 		return false
 	}
+	for _, ref := range []*SchemaRef{schema.If, schema.Then, schema.Else, schema.UnevaluatedProperties} {
+		if ref != nil && !ref.Value.IsEmpty() {
+			return false
+		}
+	}
+	if ua := schema.UnevaluatedPropertiesAllowed; ua != nil && !*ua {
+		return false
+	}
+	for _, s := range schema.PrefixItems {
+		if !s.Value.IsEmpty() {
+			return false
+		}
+	}
+	for _, s := range schema.DependentSchemas {
+		if !s.Value.IsEmpty() {
+			return false
+		}
+	}
 	if ap := schema.AdditionalProperties; ap != nil && !ap.Value.IsEmpty() {
 		return false
 	}
@@ -594,7 +719,22 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 		return errors.New("a property MUST NOT be marked as both readOnly and writeOnly being true")
 	}
 
+	if isOpenAPI3_0(ctx) {
+		if keyword := schema.openAPI3_1Keyword(); keyword != "" {
+			return fmt.Errorf("schema keyword %q requires OpenAPI 3.1", keyword)
+		}
+	}
+
+	if x := schema.XML; x != nil {
+		if err = x.Validate(ctx); err != nil {
+			return
+		}
+	}
+
 	for _, item := range schema.OneOf {
+		if validatedElsewhere(ctx, item.Ref, item.Value) {
+			continue
+		}
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
@@ -605,6 +745,9 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 	}
 
 	for _, item := range schema.AnyOf {
+		if validatedElsewhere(ctx, item.Ref, item.Value) {
+			continue
+		}
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
@@ -615,6 +758,9 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 	}
 
 	for _, item := range schema.AllOf {
+		if validatedElsewhere(ctx, item.Ref, item.Value) {
+			continue
+		}
 		v := item.Value
 		if v == nil {
 			return foundUnresolvedRef(item.Ref)
@@ -624,7 +770,26 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 		}
 	}
 
-	if ref := schema.Not; ref != nil {
+	if ref := schema.Not; ref != nil && !validatedElsewhere(ctx, ref.Ref, ref.Value) {
+		v := ref.Value
+		if v == nil {
+			return foundUnresolvedRef(ref.Ref)
+		}
+		if err = v.validate(ctx, stack); err != nil {
+			return
+		}
+	}
+
+	for _, schemaType := range schema.types() {
+		if err = schema.validateType(ctx, schemaType); err != nil {
+			return
+		}
+	}
+
+	for _, ref := range schema.subschemas() {
+		if validatedElsewhere(ctx, ref.Ref, ref.Value) {
+			continue
+		}
 		v := ref.Value
 		if v == nil {
 			return foundUnresolvedRef(ref.Ref)
@@ -634,9 +799,60 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 		}
 	}
 
-	schemaType := schema.Type
+	return
+}
+
+// openAPI3_1Keyword returns the first keyword of the schema that only OpenAPI 3.1 documents allow, if any.
+func (schema *Schema) openAPI3_1Keyword() string {
+	switch {
+	case len(schema.Types) != 0:
+		return "type"
+	case schema.ExclusiveMinValue != nil:
+		return "exclusiveMinimum"
+	case schema.ExclusiveMaxValue != nil:
+		return "exclusiveMaximum"
+	case schema.HasConst():
+		return "const"
+	case len(schema.PrefixItems) != 0:
+		return "prefixItems"
+	case len(schema.Defs) != 0:
+		return "$defs"
+	case schema.If != nil:
+		return "if"
+	case schema.Then != nil:
+		return "then"
+	case schema.Else != nil:
+		return "else"
+	case len(schema.DependentSchemas) != 0:
+		return "dependentSchemas"
+	case schema.UnevaluatedProperties != nil || schema.UnevaluatedPropertiesAllowed != nil:
+		return "unevaluatedProperties"
+	}
+	return ""
+}
+
+// subschemas returns the schemas nested in the schema, set operations excepted.
+func (schema *Schema) subschemas() []*SchemaRef {
+	var refs []*SchemaRef
+	for _, ref := range []*SchemaRef{schema.Items, schema.AdditionalProperties,
+		schema.If, schema.Then, schema.Else, schema.UnevaluatedProperties} {
+		if ref != nil {
+			refs = append(refs, ref)
+		}
+	}
+	refs = append(refs, schema.PrefixItems...)
+	for _, schemas := range []Schemas{schema.Properties, schema.DependentSchemas, schema.Defs} {
+		for _, ref := range schemas {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func (schema *Schema) validateType(ctx context.Context, schemaType string) (err error) {
 	switch schemaType {
 	case "":
+	case "null":
 	case "boolean":
 	case "number":
 		if format := schema.Format; len(format) > 0 {
@@ -682,45 +898,56 @@ func (schema *Schema) validate(ctx context.Context, stack []*Schema) (err error)
 			}
 		}
 	case "array":
-		if schema.Items == nil {
+		// OpenAPI 3.1 schemas may leave items out. Outside of a document,
+		// they are recognizable by a list of types or by prefixItems.
+		if schema.Items == nil && !isOpenAPI3_1(ctx) && len(schema.Types) == 0 && len(schema.PrefixItems) == 0 {
 			return errors.New("when schema type is 'array', schema 'items' must be non-null")
 		}
 	case "object":
 	default:
 		return fmt.Errorf("unsupported 'type' value %q", schemaType)
 	}
+	return
+}
 
-	if ref := schema.Items; ref != nil {
-		v := ref.Value
-		if v == nil {
-			return foundUnresolvedRef(ref.Ref)
-		}
-		if err = v.validate(ctx, stack); err != nil {
-			return
-		}
+// types returns the types allowed by the schema: either its OpenAPI 3.1
+// list of types or its single OpenAPI 3.0 type.
+func (schema *Schema) types() []string {
+	if len(schema.Types) != 0 {
+		return schema.Types
 	}
+	if schema.Type != "" {
+		return []string{schema.Type}
+	}
+	return nil
+}
 
-	for _, ref := range schema.Properties {
-		v := ref.Value
-		if v == nil {
-			return foundUnresolvedRef(ref.Ref)
-		}
-		if err = v.validate(ctx, stack); err != nil {
-			return
+// allowsType reports whether the schema's types allow values of the given type.
+func (schema *Schema) allowsType(typ string) bool {
+	types := schema.types()
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == typ {
+			return true
 		}
 	}
+	return false
+}
 
-	if ref := schema.AdditionalProperties; ref != nil {
-		v := ref.Value
-		if v == nil {
-			return foundUnresolvedRef(ref.Ref)
-		}
-		if err = v.validate(ctx, stack); err != nil {
-			return
+// isNullable reports whether null is allowed, either through the OpenAPI 3.0
+// "nullable" keyword or through the OpenAPI 3.1 "null" type.
+func (schema *Schema) isNullable() bool {
+	if schema.Nullable {
+		return true
+	}
+	for _, t := range schema.types() {
+		if t == "null" {
+			return true
 		}
 	}
-
-	return
+	return false
 }
 
 func (schema *Schema) IsMatching(value interface{}) bool {
@@ -819,15 +1046,21 @@ func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, val
 		}
 	}
 
+	if schema.HasConst() && !reflect.DeepEqual(normalizeJSONValue(value), normalizeJSONValue(schema.Const)) {
+		return schema.constError(settings, value)
+	}
+
 	if ref := schema.Not; ref != nil {
 		v := ref.Value
 		if v == nil {
 			return foundUnresolvedRef(ref.Ref)
 		}
 		var oldfailfast bool
+		var olddefaultsSet func()
 		oldfailfast, settings.failfast = settings.failfast, true
+		olddefaultsSet, settings.defaultsSet = settings.defaultsSet, nil
 		err := v.visitJSON(settings, value)
-		settings.failfast = oldfailfast
+		settings.failfast, settings.defaultsSet = oldfailfast, olddefaultsSet
 		if err == nil {
 			if settings.failfast {
 				return errSchema
@@ -848,9 +1081,11 @@ func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, val
 				return foundUnresolvedRef(item.Ref)
 			}
 			var oldfailfast bool
+			var olddefaultsSet func()
 			oldfailfast, settings.failfast = settings.failfast, true
+			olddefaultsSet, settings.defaultsSet = settings.defaultsSet, nil
 			err := v.visitJSON(settings, value)
-			settings.failfast = oldfailfast
+			settings.failfast, settings.defaultsSet = oldfailfast, olddefaultsSet
 			if err == nil {
 				if schema.Discriminator != nil {
 					pn := schema.Discriminator.PropertyName
@@ -891,9 +1126,11 @@ func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, val
 				return foundUnresolvedRef(item.Ref)
 			}
 			var oldfailfast bool
+			var olddefaultsSet func()
 			oldfailfast, settings.failfast = settings.failfast, true
+			olddefaultsSet, settings.defaultsSet = settings.defaultsSet, nil
 			err := v.visitJSON(settings, value)
-			settings.failfast = oldfailfast
+			settings.failfast, settings.defaultsSet = oldfailfast, olddefaultsSet
 			if err == nil {
 				ok = true
 				break
@@ -932,11 +1169,52 @@ func (schema *Schema) visitSetOperations(settings *schemaValidationSettings, val
 			}
 		}
 	}
+
+	if ref := schema.If; ref != nil {
+		v := ref.Value
+		if v == nil {
+			return foundUnresolvedRef(ref.Ref)
+		}
+		var oldfailfast bool
+		var olddefaultsSet func()
+		oldfailfast, settings.failfast = settings.failfast, true
+		olddefaultsSet, settings.defaultsSet = settings.defaultsSet, nil
+		ifErr := v.visitJSON(settings, value)
+		settings.failfast, settings.defaultsSet = oldfailfast, olddefaultsSet
+
+		field, branch := "then", schema.Then
+		if ifErr != nil {
+			field, branch = "else", schema.Else
+		}
+		if branch != nil {
+			v := branch.Value
+			if v == nil {
+				return foundUnresolvedRef(branch.Ref)
+			}
+			if err := v.visitJSON(settings, value); err != nil {
+				if settings.failfast {
+					return errSchema
+				}
+				return &SchemaError{
+					Value:       value,
+					Schema:      schema,
+					SchemaField: field,
+					Origin:      err,
+				}
+			}
+		}
+	}
 	return
 }
 
 func (schema *Schema) visitJSONNull(settings *schemaValidationSettings) (err error) {
-	if schema.Nullable {
+	if schema.HasConst() {
+		if schema.Const != nil {
+			return schema.constError(settings, nil)
+		}
+		return
+	}
+	if schema.isNullable() {
 		return
 	}
 	if settings.failfast {
@@ -950,13 +1228,54 @@ func (schema *Schema) visitJSONNull(settings *schemaValidationSettings) (err err
 	}
 }
 
+func (schema *Schema) constError(settings *schemaValidationSettings, value interface{}) error {
+	if settings.failfast {
+		return errSchema
+	}
+	return &SchemaError{
+		Value:       value,
+		Schema:      schema,
+		SchemaField: "const",
+		Reason:      "value is not the constant value",
+	}
+}
+
+// normalizeJSONValue returns a copy of value with its numbers converted to float64,
+// as decoded from JSON, so that WithConst(1) allows 1.0.
+func normalizeJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeJSONValue(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			normalized[k] = normalizeJSONValue(item)
+		}
+		return normalized
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	}
+	return value
+}
+
 func (schema *Schema) VisitJSONBoolean(value bool) error {
 	settings := newSchemaValidationSettings()
 	return schema.visitJSONBoolean(settings, value)
 }
 
 func (schema *Schema) visitJSONBoolean(settings *schemaValidationSettings, value bool) (err error) {
-	if schemaType := schema.Type; schemaType != "" && schemaType != "boolean" {
+	if !schema.allowsType("boolean") {
 		return schema.expectedType(settings, "boolean")
 	}
 	return
@@ -969,8 +1288,10 @@ func (schema *Schema) VisitJSONNumber(value float64) error {
 
 func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value float64) error {
 	var me MultiError
-	schemaType := schema.Type
-	if schemaType == "integer" {
+	if !schema.allowsType("number") && !schema.allowsType("integer") {
+		return schema.expectedType(settings, "number, integer")
+	}
+	if !schema.allowsType("number") {
 		if bigFloat := big.NewFloat(value); !bigFloat.IsInt() {
 			if settings.failfast {
 				return errSchema
@@ -986,8 +1307,6 @@ func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value
 			}
 			me = append(me, err)
 		}
-	} else if schemaType != "" && schemaType != "number" {
-		return schema.expectedType(settings, "number, integer")
 	}
 
 	// "exclusiveMinimum"
@@ -1024,6 +1343,40 @@ func (schema *Schema) visitJSONNumber(settings *schemaValidationSettings, value
 		me = append(me, err)
 	}
 
+	// "exclusiveMinimum" (OpenAPI 3.1)
+	if v := schema.ExclusiveMinValue; v != nil && !(*v < value) {
+		if settings.failfast {
+			return errSchema
+		}
+		err := &SchemaError{
+			Value:       value,
+			Schema:      schema,
+			SchemaField: "exclusiveMinimum",
+			Reason:      fmt.Sprintf("number must be more than %g", *v),
+		}
+		if !settings.multiError {
+			return err
+		}
+		me = append(me, err)
+	}
+
+	// "exclusiveMaximum" (OpenAPI 3.1)
+	if v := schema.ExclusiveMaxValue; v != nil && !(*v > value) {
+		if settings.failfast {
+			return errSchema
+		}
+		err := &SchemaError{
+			Value:       value,
+			Schema:      schema,
+			SchemaField: "exclusiveMaximum",
+			Reason:      fmt.Sprintf("number must be less than %g", *v),
+		}
+		if !settings.multiError {
+			return err
+		}
+		me = append(me, err)
+	}
+
 	// "minimum"
 	if v := schema.Min; v != nil && !(*v <= value) {
 		if settings.failfast {
@@ -1091,7 +1444,7 @@ func (schema *Schema) VisitJSONString(value string) error {
 }
 
 func (schema *Schema) visitJSONString(settings *schemaValidationSettings, value string) error {
-	if schemaType := schema.Type; schemaType != "" && schemaType != "string" {
+	if !schema.allowsType("string") {
 		return schema.expectedType(settings, "string")
 	}
 
@@ -1210,7 +1563,7 @@ func (schema *Schema) VisitJSONArray(value []interface{}) error {
 }
 
 func (schema *Schema) visitJSONArray(settings *schemaValidationSettings, value []interface{}) error {
-	if schemaType := schema.Type; schemaType != "" && schemaType != "array" {
+	if !schema.allowsType("array") {
 		return schema.expectedType(settings, "array")
 	}
 
@@ -1272,13 +1625,37 @@ func (schema *Schema) visitJSONArray(settings *schemaValidationSettings, value [
 		me = append(me, err)
 	}
 
+	// "prefixItems"
+	for i, itemSchemaRef := range schema.PrefixItems {
+		if i >= len(value) {
+			break
+		}
+		itemSchema := itemSchemaRef.Value
+		if itemSchema == nil {
+			return foundUnresolvedRef(itemSchemaRef.Ref)
+		}
+		if err := itemSchema.visitJSON(settings, value[i]); err != nil {
+			err = markSchemaErrorIndex(err, i)
+			if !settings.multiError {
+				return err
+			}
+			if itemMe, ok := err.(MultiError); ok {
+				me = append(me, itemMe...)
+			} else {
+				me = append(me, err)
+			}
+		}
+	}
+
 	// "items"
 	if itemSchemaRef := schema.Items; itemSchemaRef != nil {
 		itemSchema := itemSchemaRef.Value
 		if itemSchema == nil {
 			return foundUnresolvedRef(itemSchemaRef.Ref)
 		}
-		for i, item := range value {
+		// Items evaluated by "prefixItems" are not evaluated again.
+		for i := len(schema.PrefixItems); i < len(value); i++ {
+			item := value[i]
 			if err := itemSchema.visitJSON(settings, item); err != nil {
 				err = markSchemaErrorIndex(err, i)
 				if !settings.multiError {
@@ -1306,7 +1683,7 @@ func (schema *Schema) VisitJSONObject(value map[string]interface{}) error {
 }
 
 func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value map[string]interface{}) error {
-	if schemaType := schema.Type; schemaType != "" && schemaType != "object" {
+	if !schema.allowsType("object") {
 		return schema.expectedType(settings, "object")
 	}
 
@@ -1314,6 +1691,23 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 
 	// "properties"
 	properties := schema.Properties
+	if f := settings.defaultsSet; f != nil {
+		for _, k := range sortedSchemaNames(properties) {
+			if _, ok := value[k]; ok {
+				continue
+			}
+			p := properties[k].Value
+			if p == nil || p.Default == nil {
+				continue
+			}
+			if (p.ReadOnly && settings.asreq) || (p.WriteOnly && settings.asrep) {
+				continue
+			}
+			// The value is changed by the validation of its own properties
+			value[k] = normalizeJSONValue(p.Default)
+			f()
+		}
+	}
 	lenValue := int64(len(value))
 
 	// "minProperties"
@@ -1440,6 +1834,85 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 		}
 	}
 
+	// "dependentSchemas"
+	for _, k := range sortedSchemaNames(schema.DependentSchemas) {
+		if _, ok := value[k]; !ok {
+			continue
+		}
+		ref := schema.DependentSchemas[k]
+		v := ref.Value
+		if v == nil {
+			return foundUnresolvedRef(ref.Ref)
+		}
+		if err := v.visitJSON(settings, value); err != nil {
+			if settings.failfast {
+				return errSchema
+			}
+			err = &SchemaError{
+				Value:       value,
+				Schema:      schema,
+				SchemaField: "dependentSchemas",
+				Reason:      fmt.Sprintf("property %q is present so the object must match its dependent schema", k),
+				Origin:      err,
+			}
+			if !settings.multiError {
+				return err
+			}
+			me = append(me, err)
+		}
+	}
+
+	// "unevaluatedProperties"
+	allowed := schema.UnevaluatedPropertiesAllowed
+	if unevaluated := schema.UnevaluatedProperties; unevaluated != nil || (allowed != nil && !*allowed) {
+		evaluated := make(map[string]struct{}, len(value))
+		if !schema.evaluatedProperties(value, evaluated, true) {
+			keys := make([]string, 0, len(value))
+			for k := range value {
+				if _, ok := evaluated[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				var err error
+				if unevaluated != nil {
+					v := unevaluated.Value
+					if v == nil {
+						return foundUnresolvedRef(unevaluated.Ref)
+					}
+					if err = v.visitJSON(settings, value[k]); err != nil {
+						if settings.failfast {
+							return errSchema
+						}
+						err = markSchemaErrorKey(err, k)
+					}
+				} else {
+					if settings.failfast {
+						return errSchema
+					}
+					err = &SchemaError{
+						Value:       value,
+						Schema:      schema,
+						SchemaField: "unevaluatedProperties",
+						Reason:      fmt.Sprintf("property %q is unsupported", k),
+					}
+				}
+				if err == nil {
+					continue
+				}
+				if !settings.multiError {
+					return err
+				}
+				if v, ok := err.(MultiError); ok {
+					me = append(me, v...)
+					continue
+				}
+				me = append(me, err)
+			}
+		}
+	}
+
 	if len(me) > 0 {
 		return me
 	}
@@ -1447,6 +1920,62 @@ func (schema *Schema) visitJSONObject(settings *schemaValidationSettings, value
 	return nil
 }
 
+// evaluatedProperties adds to evaluated the properties of value that are
+// evaluated by the schema or by its successfully applied in-place subschemas,
+// as required by "unevaluatedProperties".
+// It returns true when every property of value is evaluated.
+func (schema *Schema) evaluatedProperties(value map[string]interface{}, evaluated map[string]struct{}, root bool) bool {
+	if schema.AdditionalProperties != nil || schema.AdditionalPropertiesAllowed != nil {
+		return true
+	}
+	if !root && (schema.UnevaluatedProperties != nil || schema.UnevaluatedPropertiesAllowed != nil) {
+		return true
+	}
+	for k := range schema.Properties {
+		if _, ok := value[k]; ok {
+			evaluated[k] = struct{}{}
+		}
+	}
+
+	var applied []*SchemaRef
+	applied = append(applied, schema.AllOf...)
+	for _, ref := range append(append(SchemaRefs{}, schema.AnyOf...), schema.OneOf...) {
+		if ref.Value != nil && ref.Value.IsMatching(value) {
+			applied = append(applied, ref)
+		}
+	}
+	if ref := schema.If; ref != nil && ref.Value != nil {
+		if ref.Value.IsMatching(value) {
+			applied = append(applied, ref, schema.Then)
+		} else {
+			applied = append(applied, schema.Else)
+		}
+	}
+	for k, ref := range schema.DependentSchemas {
+		if _, ok := value[k]; ok {
+			applied = append(applied, ref)
+		}
+	}
+	for _, ref := range applied {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if ref.Value.evaluatedProperties(value, evaluated, false) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedSchemaNames(schemas Schemas) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (schema *Schema) expectedType(settings *schemaValidationSettings, typ string) error {
 	if settings.failfast {
 		return errSchema
@@ -1455,7 +1984,7 @@ func (schema *Schema) expectedType(settings *schemaValidationSettings, typ strin
 		Value:       typ,
 		Schema:      schema,
 		SchemaField: "type",
-		Reason:      "Field must be set to " + schema.Type + " or not be present",
+		Reason:      "Field must be set to " + strings.Join(schema.types(), ", ") + " or not be present",
 	}
 }
 
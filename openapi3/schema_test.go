@@ -923,6 +923,200 @@ var schemaExamples = []schemaExample{
 			4,
 		},
 	},
+
+	{
+		Title:  "TYPE LIST (OPENAPI 3.1)",
+		Schema: NewSchema().WithTypes("string", "null"),
+		Serialization: map[string]interface{}{
+			"type": []interface{}{"string", "null"},
+		},
+		AllValid: []interface{}{
+			nil,
+			"",
+			"abc",
+		},
+		AllInvalid: []interface{}{
+			false,
+			3.14,
+			[]interface{}{},
+			map[string]interface{}{},
+		},
+	},
+
+	{
+		Title:  "TYPE LIST OF INTEGER AND BOOLEAN (OPENAPI 3.1)",
+		Schema: NewSchema().WithTypes("integer", "boolean"),
+		Serialization: map[string]interface{}{
+			"type": []interface{}{"integer", "boolean"},
+		},
+		AllValid: []interface{}{
+			true,
+			0,
+			42,
+		},
+		AllInvalid: []interface{}{
+			nil,
+			3.14,
+			"",
+		},
+	},
+
+	{
+		Title: "NUMERIC EXCLUSIVE BOUNDS (OPENAPI 3.1)",
+		Schema: NewFloat64Schema().
+			WithExclusiveMinValue(1).
+			WithExclusiveMaxValue(3),
+		Serialization: map[string]interface{}{
+			"type":             "number",
+			"exclusiveMinimum": 1,
+			"exclusiveMaximum": 3,
+		},
+		AllValid: []interface{}{
+			1.5,
+			2,
+			2.9,
+		},
+		AllInvalid: []interface{}{
+			0,
+			1,
+			3,
+			4,
+		},
+	},
+
+	{
+		Title:  "CONST (OPENAPI 3.1)",
+		Schema: NewSchema().WithConst("v1"),
+		Serialization: map[string]interface{}{
+			"const": "v1",
+		},
+		AllValid: []interface{}{
+			"v1",
+		},
+		AllInvalid: []interface{}{
+			nil,
+			"v2",
+			1,
+		},
+	},
+
+	{
+		Title: "PREFIX ITEMS (OPENAPI 3.1)",
+		Schema: &Schema{
+			Types: []string{"array"},
+			PrefixItems: SchemaRefs{
+				NewStringSchema().NewRef(),
+				NewIntegerSchema().NewRef(),
+			},
+			Items: NewBoolSchema().NewRef(),
+		},
+		Serialization: map[string]interface{}{
+			"type": []interface{}{"array"},
+			"prefixItems": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "integer"},
+			},
+			"items": map[string]interface{}{"type": "boolean"},
+		},
+		AllValid: []interface{}{
+			[]interface{}{},
+			[]interface{}{"a"},
+			[]interface{}{"a", 1},
+			[]interface{}{"a", 1, true, false},
+		},
+		AllInvalid: []interface{}{
+			[]interface{}{1},
+			[]interface{}{"a", "b"},
+			[]interface{}{"a", 1, "c"},
+		},
+	},
+
+	{
+		Title: "IF THEN ELSE (OPENAPI 3.1)",
+		Schema: &Schema{
+			Type: "object",
+			If: (&Schema{
+				Properties: Schemas{"country": NewSchema().WithConst("US").NewRef()},
+				Required:   []string{"country"},
+			}).NewRef(),
+			Then: (&Schema{
+				Properties: Schemas{"zip": NewStringSchema().WithPattern("^[0-9]{5}$").NewRef()},
+			}).NewRef(),
+			Else: (&Schema{
+				Properties: Schemas{"zip": NewStringSchema().WithMaxLength(10).NewRef()},
+			}).NewRef(),
+		},
+		Serialization: map[string]interface{}{
+			"type": "object",
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"country": map[string]interface{}{"const": "US"}},
+				"required":   []interface{}{"country"},
+			},
+			"then": map[string]interface{}{
+				"properties": map[string]interface{}{"zip": map[string]interface{}{"type": "string", "pattern": "^[0-9]{5}$"}},
+			},
+			"else": map[string]interface{}{
+				"properties": map[string]interface{}{"zip": map[string]interface{}{"type": "string", "maxLength": 10}},
+			},
+		},
+		AllValid: []interface{}{
+			map[string]interface{}{"country": "US", "zip": "12345"},
+			map[string]interface{}{"country": "FR", "zip": "75001"},
+			map[string]interface{}{"zip": "K1A 0B1"},
+		},
+		AllInvalid: []interface{}{
+			map[string]interface{}{"country": "US", "zip": "K1A 0B1"},
+			map[string]interface{}{"country": "FR", "zip": "a very long postal code"},
+		},
+	},
+
+	{
+		Title: "DEPENDENT SCHEMAS AND UNEVALUATED PROPERTIES (OPENAPI 3.1)",
+		Schema: &Schema{
+			Type: "object",
+			Properties: Schemas{
+				"name": NewStringSchema().NewRef(),
+			},
+			DependentSchemas: Schemas{
+				"credit_card": (&Schema{
+					Properties: Schemas{"billing_address": NewStringSchema().NewRef()},
+					Required:   []string{"billing_address"},
+				}).NewRef(),
+			},
+			AllOf: SchemaRefs{
+				(&Schema{Properties: Schemas{"credit_card": NewStringSchema().NewRef()}}).NewRef(),
+			},
+			UnevaluatedPropertiesAllowed: BoolPtr(false),
+		},
+		Serialization: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name": map[string]interface{}{"type": "string"},
+			},
+			"dependentSchemas": map[string]interface{}{
+				"credit_card": map[string]interface{}{
+					"properties": map[string]interface{}{"billing_address": map[string]interface{}{"type": "string"}},
+					"required":   []interface{}{"billing_address"},
+				},
+			},
+			"allOf": []interface{}{
+				map[string]interface{}{
+					"properties": map[string]interface{}{"credit_card": map[string]interface{}{"type": "string"}},
+				},
+			},
+			"unevaluatedProperties": false,
+		},
+		AllValid: []interface{}{
+			map[string]interface{}{},
+			map[string]interface{}{"name": "John"},
+			map[string]interface{}{"name": "John", "credit_card": "5555", "billing_address": "Main St."},
+		},
+		AllInvalid: []interface{}{
+			map[string]interface{}{"name": "John", "credit_card": "5555"},
+			map[string]interface{}{"name": "John", "billing_address": "Main St."},
+			map[string]interface{}{"name": "John", "age": 42},
+		},
+	},
 }
 
 type schemaTypeExample struct {
@@ -7,6 +7,8 @@ type schemaValidationSettings struct {
 	failfast     bool
 	multiError   bool
 	asreq, asrep bool // exclusive (XOR) fields
+
+	defaultsSet func()
 }
 
 // FailFast returns schema validation errors quicker.
@@ -25,6 +27,12 @@ func VisitAsResponse() SchemaValidationOption {
 	return func(s *schemaValidationSettings) { s.asreq, s.asrep = false, true }
 }
 
+// DefaultsSet makes validation of objects fill in absent properties with
+// their schema's default value. The given callback is executed each time a default is set.
+func DefaultsSet(f func()) SchemaValidationOption {
+	return func(s *schemaValidationSettings) { s.defaultsSet = f }
+}
+
 func newSchemaValidationSettings(opts ...SchemaValidationOption) *schemaValidationSettings {
 	settings := &schemaValidationSettings{}
 	for _, opt := range opts {
@@ -2,6 +2,7 @@ package openapi3
 
 import (
 	"context"
+	"strconv"
 )
 
 type SecurityRequirements []SecurityRequirement
@@ -16,8 +17,8 @@ func (srs *SecurityRequirements) With(securityRequirement SecurityRequirement) *
 }
 
 func (value SecurityRequirements) Validate(ctx context.Context) error {
-	for _, item := range value {
-		if err := item.Validate(ctx); err != nil {
+	for i, item := range value {
+		if err := validateAt(ctx, item.Validate, strconv.Itoa(i)); err != nil {
 			return err
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/jsoninfo"
@@ -16,8 +17,8 @@ type Servers []*Server
 
 // Validate ensures servers are per the OpenAPIv3 specification.
 func (value Servers) Validate(ctx context.Context) error {
-	for _, v := range value {
-		if err := v.Validate(ctx); err != nil {
+	for i, v := range value {
+		if err := validateAt(ctx, v.Validate, strconv.Itoa(i)); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,222 @@
+package openapi3
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// SourceLocation is where a value was found in the files a document was loaded from.
+// Lines and columns start at 1.
+type SourceLocation struct {
+	// File is the location of the file, empty for data loaded without a path.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (location SourceLocation) String() string {
+	if location.File == "" {
+		return fmt.Sprintf("%d:%d", location.Line, location.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", location.File, location.Line, location.Column)
+}
+
+// SourceLocation returns where value, an element of the document such as a *Schema,
+// an *Operation or a *Parameter, was defined.
+// Values shared through references are located where they are defined,
+// the *XRef holding a reference being located where it is used.
+// This can be used to report the *Schema of a SchemaError for instance.
+//
+// Locations are only known for documents loaded with Loader.IncludeSourceLocations.
+func (doc *T) SourceLocation(value interface{}) (SourceLocation, bool) {
+	if source := doc.sourceMap.lookup(value); source != nil {
+		return source.SourceLocation, true
+	}
+	return SourceLocation{}, false
+}
+
+// SourceLocationAt returns where the element at the given JSON pointer
+// (e.g. "/paths/~1pets/get/responses") was defined, following references.
+// Elements that are not located themselves, such as a string field,
+// are located by their key in the closest element that is.
+//
+// Locations are only known for documents loaded with Loader.IncludeSourceLocations.
+func (doc *T) SourceLocationAt(pointer string) (SourceLocation, bool) {
+	if doc.sourceMap == nil || (pointer != "" && pointer[0] != '/') {
+		return SourceLocation{}, false
+	}
+	var tokens []string
+	if pointer != "" {
+		tokens = strings.Split(pointer[1:], "/")
+	}
+
+	var cursor interface{} = doc
+	var closest *valueSource
+	var key string // token following the closest located element
+	for _, token := range tokens {
+		token = unescapeRefString(token)
+		if source := doc.sourceMap.lookup(cursor); source != nil {
+			closest, key = source, token
+		}
+		if s, ok := cursor.(*SchemaRef); ok && s.Value == nil {
+			cursor = nil
+			break
+		}
+		next, err := drillIntoField(cursor, token)
+		if err != nil {
+			cursor = nil
+			break
+		}
+		cursor = next
+	}
+	if source := doc.sourceMap.lookup(cursor); source != nil {
+		return source.SourceLocation, true
+	}
+	if closest == nil {
+		return SourceLocation{}, false
+	}
+	if location, ok := closest.fields[key]; ok {
+		return location, true
+	}
+	return closest.SourceLocation, true
+}
+
+// sourceMap holds the locations of the values of the documents loaded by a Loader.
+type sourceMap struct {
+	values map[interface{}]*valueSource
+}
+
+type valueSource struct {
+	SourceLocation
+
+	// locations of the keys of an object or items of an array
+	fields map[string]SourceLocation
+}
+
+func (m *sourceMap) lookup(value interface{}) *valueSource {
+	if m == nil {
+		return nil
+	}
+	// Only pointers are recorded, other values may not even be comparable
+	if v := reflect.ValueOf(value); v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	return m.values[value]
+}
+
+// add records the locations of value, freshly unmarshaled from data.
+func (m *sourceMap) add(value interface{}, data []byte, location *url.URL) {
+	var node yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &node); err != nil {
+		// The data was parsed already: locations are just missing
+		return
+	}
+	file := ""
+	if location != nil {
+		file = location.String()
+	}
+	sources := make(map[string]*valueSource)
+	indexNode(sources, &node, "", SourceLocation{File: file})
+	m.record(sources, reflect.ValueOf(value), "")
+}
+
+// indexNode maps the JSON pointers of the elements under node to their locations.
+func indexNode(sources map[string]*valueSource, node *yamlv3.Node, pointer string, location SourceLocation) {
+	locate := func(node *yamlv3.Node) SourceLocation {
+		location.Line, location.Column = node.Line, node.Column
+		return location
+	}
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		if len(node.Content) != 0 {
+			sources[pointer] = &valueSource{SourceLocation: locate(node.Content[0])}
+			indexNode(sources, node.Content[0], pointer, location)
+		}
+	case yamlv3.MappingNode:
+		source := sources[pointer]
+		source.fields = make(map[string]SourceLocation, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			p := JoinJSONPointer(pointer, key.Value)
+			source.fields[key.Value] = locate(key)
+			sources[p] = &valueSource{SourceLocation: locate(key)}
+			indexNode(sources, value, p, location)
+		}
+	case yamlv3.SequenceNode:
+		source := sources[pointer]
+		source.fields = make(map[string]SourceLocation, len(node.Content))
+		for i, item := range node.Content {
+			index := strconv.Itoa(i)
+			p := JoinJSONPointer(pointer, index)
+			source.fields[index] = locate(item)
+			sources[p] = &valueSource{SourceLocation: locate(item)}
+			indexNode(sources, item, p, location)
+		}
+	}
+}
+
+// record walks v alongside the locations indexed by JSON pointer,
+// recording them for every pointer to a struct.
+// References are not followed: their values are recorded where they are defined.
+func (m *sourceMap) record(sources map[string]*valueSource, v reflect.Value, pointer string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return
+		}
+		if source, ok := sources[pointer]; ok {
+			if _, ok := m.values[v.Interface()]; !ok {
+				m.values[v.Interface()] = source
+			}
+		}
+		m.record(sources, v.Elem(), pointer)
+
+	case reflect.Struct:
+		t := v.Type()
+		if isRef(t) {
+			if v.Field(0).String() == "" {
+				m.record(sources, v.Field(1), pointer)
+			}
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" || field.Name == "ExtensionProps" {
+				continue
+			}
+			if field.Anonymous {
+				// e.g. the Parameter of a Header
+				if f := v.Field(i); f.Kind() == reflect.Struct && f.CanAddr() {
+					m.record(sources, f.Addr(), pointer)
+				}
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				name = strings.Split(field.Tag.Get("multijson"), ",")[0]
+			}
+			if name != "" {
+				m.record(sources, v.Field(i), JoinJSONPointer(pointer, name))
+			}
+		}
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			m.record(sources, iter.Value(), JoinJSONPointer(pointer, iter.Key().String()))
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			m.record(sources, v.Index(i), JoinJSONPointer(pointer, strconv.Itoa(i)))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLocations(t *testing.T) {
+	loader := NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.IncludeSourceLocations = true
+	doc, err := loader.LoadFromFile("testdata/internalizeRefs/openapi.yml")
+	require.NoError(t, err)
+
+	const root = "testdata/internalizeRefs/openapi.yml"
+	const common = "testdata/internalizeRefs/common.yml"
+	const paths = "testdata/internalizeRefs/paths.yml"
+
+	location, ok := doc.SourceLocation(doc)
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: root, Line: 1, Column: 1}, location)
+
+	get := doc.Paths["/pets"].Get
+	location, ok = doc.SourceLocation(get)
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: root, Line: 7, Column: 5}, location)
+	require.Equal(t, root+":7:5", location.String())
+
+	// The reference is located where it is used, its value where it is defined
+	location, ok = doc.SourceLocation(get.Parameters[0])
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: root, Line: 9, Column: 9}, location)
+	location, ok = doc.SourceLocation(get.Parameters[0].Value)
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: common, Line: 8, Column: 5}, location)
+
+	location, ok = doc.SourceLocation(doc.Components.Schemas["Pet"].Value)
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: "testdata/internalizeRefs/pets.yml", Line: 1, Column: 1}, location)
+
+	location, ok = doc.SourceLocationAt("/paths/~1owners/get/responses/200/description")
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: paths, Line: 12, Column: 11}, location)
+
+	location, ok = doc.SourceLocationAt("/paths/~1pets/get/responses/default/content/application~1json/schema/properties/message")
+	require.True(t, ok)
+	require.Equal(t, SourceLocation{File: common, Line: 26, Column: 9}, location)
+
+	_, ok = doc.SourceLocationAt("/paths/~1missing")
+	require.True(t, ok, "located by the closest element")
+	_, ok = doc.SourceLocationAt("paths")
+	require.False(t, ok)
+
+	doc, err = NewLoader().LoadFromData([]byte(`{"openapi": "3.0.0", "info": {}, "paths": {}}`))
+	require.NoError(t, err)
+	_, ok = doc.SourceLocation(doc)
+	require.False(t, ok)
+	_, ok = doc.SourceLocationAt("/info")
+	require.False(t, ok)
+}
+
+func TestSourceLocationsOfValidationIssues(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info:
+  title: Issues
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: body
+      responses:
+        '200':
+          description: Pets
+`)
+	loader := NewLoader()
+	loader.IncludeSourceLocations = true
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	err = doc.Validate(context.Background(), CollectAllErrors())
+	var issues ValidationIssues
+	require.True(t, errors.As(err, &issues))
+	require.Len(t, issues, 1)
+	require.Equal(t, &SourceLocation{Line: 10, Column: 9}, issues[0].Location)
+	require.EqualError(t, issues[0], `10:9: /paths/~1pets/get/parameters/0: parameter can't have 'in' value "body"`)
+}
@@ -0,0 +1,102 @@
+package openapi3
+
+import (
+	"context"
+	"strings"
+)
+
+// Severity tells how serious a ValidationIssue is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationIssue describes one problem found in a document.
+type ValidationIssue struct {
+	// Pointer is the JSON pointer of the invalid element in the document
+	// (e.g. "/paths/~1pets/get/responses").
+	Pointer  string   `json:"pointer"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity"`
+
+	// Location is where the invalid element was defined,
+	// known when the document was loaded with Loader.IncludeSourceLocations.
+	Location *SourceLocation `json:"location,omitempty"`
+}
+
+func (issue *ValidationIssue) Error() string {
+	message := issue.Message
+	if issue.Pointer != "" {
+		message = issue.Pointer + ": " + message
+	}
+	if issue.Location != nil {
+		message = issue.Location.String() + ": " + message
+	}
+	return message
+}
+
+// ValidationIssues is returned by T.Validate with the CollectAllErrors option.
+type ValidationIssues []*ValidationIssue
+
+func (issues ValidationIssues) Error() string {
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		messages = append(messages, issue.Error())
+	}
+	return strings.Join(messages, " | ")
+}
+
+type validationIssuesKey struct{}
+type validationPointerKey struct{}
+
+type issueCollector struct {
+	doc    *T
+	issues ValidationIssues
+}
+
+func (c *issueCollector) add(pointer string, message string) {
+	issue := &ValidationIssue{
+		Pointer:  pointer,
+		Message:  message,
+		Severity: SeverityError,
+	}
+	if location, ok := c.doc.SourceLocationAt(pointer); ok {
+		issue.Location = &location
+	}
+	c.issues = append(c.issues, issue)
+}
+
+// validateAt runs validate on the element found at the given JSON pointer tokens,
+// relative to the element being validated.
+// When collecting all errors, the error is recorded at the element's pointer and nil is returned
+// so that validation goes on.
+func validateAt(ctx context.Context, validate func(context.Context) error, tokens ...string) error {
+	collector := issueCollectorFrom(ctx)
+	if collector == nil {
+		return validate(ctx)
+	}
+	pointer, _ := ctx.Value(validationPointerKey{}).(string)
+	pointer = JoinJSONPointer(pointer, tokens...)
+	if err := validate(context.WithValue(ctx, validationPointerKey{}, pointer)); err != nil {
+		collector.add(pointer, err.Error())
+	}
+	return nil
+}
+
+// validatedElsewhere tells whether a reference can be skipped because,
+// when collecting all errors, what it points to is validated among the components.
+func validatedElsewhere(ctx context.Context, ref string, value interface{}) bool {
+	collector := issueCollectorFrom(ctx)
+	return collector != nil && collector.doc.isComponent(ref, value)
+}
+
+func issueCollectorFrom(ctx context.Context) *issueCollector {
+	// Documents are often validated with a loader's nil Context
+	if ctx == nil {
+		return nil
+	}
+	collector, _ := ctx.Value(validationIssuesKey{}).(*issueCollector)
+	return collector
+}
@@ -0,0 +1,90 @@
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCollectAllErrors(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info:
+  title: Issues
+  version: 1.0.0
+paths:
+  /pets:
+    get: {}
+  /pets/{id}:
+    get:
+      responses:
+        '200':
+          description: A pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Both'
+  /owners:
+    get:
+      parameters:
+      - name: limit
+        in: body
+        schema:
+          type: integer
+      - name: offset
+        in: query
+        schema:
+          type: integer
+      responses:
+        '200':
+          description: Owners
+          content:
+            application/json:
+              schema:
+                type: string
+              example: {}
+components:
+  schemas:
+    Bad Name:
+      type: string
+    Both:
+      type: string
+      readOnly: true
+      writeOnly: true
+`)
+	loader := NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	err = doc.Validate(context.Background())
+	require.EqualError(t, err, `invalid components: identifier "Bad Name" is not supported by OpenAPIv3 standard (regexp: "^[a-zA-Z0-9._-]+$")`)
+
+	err = doc.Validate(context.Background(), CollectAllErrors(), EnableExamplesValidation())
+	var issues ValidationIssues
+	require.True(t, errors.As(err, &issues))
+	var pointers []string
+	for _, issue := range issues {
+		require.Equal(t, SeverityError, issue.Severity)
+		pointers = append(pointers, issue.Pointer)
+	}
+	require.Equal(t, []string{
+		"/components/schemas/Bad Name",
+		"/components/schemas/Both",
+		"/paths/~1owners/get/parameters/0",
+		"/paths/~1pets/get",
+		"/paths/~1pets~1{id}/get",
+		"/paths/~1owners/get/responses/200/content/application~1json/example",
+	}, pointers)
+	require.Equal(t, "a property MUST NOT be marked as both readOnly and writeOnly being true", issues[1].Message)
+	require.Equal(t, `parameter can't have 'in' value "body"`, issues[2].Message)
+	require.Equal(t, "value of responses must be an object", issues[3].Message)
+	require.Equal(t, "operation GET /pets/{id} must define exactly all path parameters", issues[4].Message)
+	require.Equal(t, "does not match its schema: Field must be set to string or not be present", issues[5].Message)
+
+	data, err := json.Marshal(issues[3])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"pointer":"/paths/~1pets/get","message":"value of responses must be an object","severity":"error"}`, string(data))
+}
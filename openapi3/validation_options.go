@@ -0,0 +1,52 @@
+package openapi3
+
+import "context"
+
+// ValidationOption describes options a user has when validating an OpenAPI document.
+type ValidationOption func(*validationSettings)
+
+type validationSettings struct {
+	examplesValidation bool
+	collectAllErrors   bool
+}
+
+// EnableExamplesValidation makes T.Validate check every example and default value
+// of the document against the schema it describes.
+// The errors are reported as a MultiError of *ExampleError.
+func EnableExamplesValidation() ValidationOption {
+	return func(s *validationSettings) { s.examplesValidation = true }
+}
+
+// CollectAllErrors makes T.Validate go on after the first error and return
+// every problem found as ValidationIssues, each with the JSON pointer of the invalid element.
+func CollectAllErrors() ValidationOption {
+	return func(s *validationSettings) { s.collectAllErrors = true }
+}
+
+// openAPI3_1Key holds whether the document being validated is an OpenAPI 3.1 one.
+// It is absent when validating elements on their own.
+type openAPI3_1Key struct{}
+
+func isOpenAPI3_1(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	is, _ := ctx.Value(openAPI3_1Key{}).(bool)
+	return is
+}
+
+func isOpenAPI3_0(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	is, ok := ctx.Value(openAPI3_1Key{}).(bool)
+	return ok && !is
+}
+
+func newValidationSettings(opts ...ValidationOption) *validationSettings {
+	settings := &validationSettings{}
+	for _, opt := range opts {
+		opt(settings)
+	}
+	return settings
+}
@@ -0,0 +1,444 @@
+package openapi3
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WalkAction tells Walk how to go on after a callback of a Visitor.
+type WalkAction int
+
+const (
+	// WalkContinue walks the element's children.
+	WalkContinue WalkAction = iota
+	// WalkSkip does not walk the element's children.
+	WalkSkip
+	// WalkRemove removes the element from its parent.
+	WalkRemove
+	// WalkStop ends the walk.
+	WalkStop
+)
+
+// Visitor holds the callbacks T.Walk calls on the elements of a document,
+// with their JSON pointer (e.g. "/paths/~1pets/get/responses/200").
+// Nil callbacks are skipped.
+//
+// An element can be replaced by setting what its callback is given a pointer to,
+// for instance the Ref or Value of a *SchemaRef.
+type Visitor struct {
+	OnPathItem       func(pointer string, pathItem *PathItem) WalkAction
+	OnOperation      func(pointer string, method string, operation *Operation) WalkAction
+	OnParameter      func(pointer string, parameter *ParameterRef) WalkAction
+	OnRequestBody    func(pointer string, requestBody *RequestBodyRef) WalkAction
+	OnResponse       func(pointer string, response *ResponseRef) WalkAction
+	OnHeader         func(pointer string, header *HeaderRef) WalkAction
+	OnMediaType      func(pointer string, mediaType *MediaType) WalkAction
+	OnSchema         func(pointer string, schema *SchemaRef) WalkAction
+	OnExample        func(pointer string, example *ExampleRef) WalkAction
+	OnLink           func(pointer string, link *LinkRef) WalkAction
+	OnCallback       func(pointer string, callback *CallbackRef) WalkAction
+	OnSecurityScheme func(pointer string, securityScheme *SecuritySchemeRef) WalkAction
+}
+
+// Walk calls the callbacks of visitor on the elements of the document:
+// components first, then paths and webhooks, each in the order of their keys.
+//
+// References are followed: callbacks are called on every occurrence of a value,
+// but its children are only walked the first time it is reached,
+// which is where it is defined for components. This makes Walk stop on recursive schemas.
+func (doc *T) Walk(visitor *Visitor) {
+	w := &walker{visitor: visitor, visited: make(map[interface{}]struct{})}
+
+	components := &doc.Components
+	w.schemas("/components/schemas", components.Schemas)
+	for _, name := range sortedMapKeys(reflect.ValueOf(components.Parameters)) {
+		if w.parameter(JoinJSONPointer("/components/parameters", name), components.Parameters[name]) {
+			delete(components.Parameters, name)
+		}
+	}
+	w.headers("/components/headers", components.Headers)
+	for _, name := range sortedMapKeys(reflect.ValueOf(components.RequestBodies)) {
+		if w.requestBody(JoinJSONPointer("/components/requestBodies", name), components.RequestBodies[name]) {
+			delete(components.RequestBodies, name)
+		}
+	}
+	w.responses("/components/responses", components.Responses)
+	for _, name := range sortedMapKeys(reflect.ValueOf(components.SecuritySchemes)) {
+		ref := components.SecuritySchemes[name]
+		if ref == nil || w.stopped || w.visitor.OnSecurityScheme == nil {
+			continue
+		}
+		if _, remove := w.apply(w.visitor.OnSecurityScheme(JoinJSONPointer("/components/securitySchemes", name), ref)); remove {
+			delete(components.SecuritySchemes, name)
+		}
+	}
+	w.examples("/components/examples", components.Examples)
+	w.links("/components/links", components.Links)
+	w.callbacks("/components/callbacks", components.Callbacks)
+
+	for _, path := range sortedMapKeys(reflect.ValueOf(doc.Paths)) {
+		if w.pathItem(JoinJSONPointer("/paths", path), doc.Paths[path]) {
+			delete(doc.Paths, path)
+		}
+	}
+	for _, name := range sortedMapKeys(reflect.ValueOf(doc.Webhooks)) {
+		if w.pathItem(JoinJSONPointer("/webhooks", name), doc.Webhooks[name]) {
+			delete(doc.Webhooks, name)
+		}
+	}
+}
+
+type walker struct {
+	visitor *Visitor
+
+	// values whose children were walked
+	visited map[interface{}]struct{}
+
+	stopped bool
+}
+
+// apply applies the action returned by a callback.
+func (w *walker) apply(action WalkAction) (descend, remove bool) {
+	switch action {
+	case WalkContinue:
+		return true, false
+	case WalkRemove:
+		return false, true
+	case WalkStop:
+		w.stopped = true
+	}
+	return false, false
+}
+
+// enter returns whether the children of value are to be walked, marking them walked.
+func (w *walker) enter(value interface{}) bool {
+	if w.stopped || reflect.ValueOf(value).IsNil() {
+		return false
+	}
+	if _, ok := w.visited[value]; ok {
+		return false
+	}
+	w.visited[value] = struct{}{}
+	return true
+}
+
+// The methods below walk an element and its children,
+// returning whether it is to be removed from its parent.
+
+func (w *walker) pathItem(pointer string, pathItem *PathItem) bool {
+	if pathItem == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnPathItem; f != nil {
+		descend, remove = w.apply(f(pointer, pathItem))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(pathItem) {
+		return false
+	}
+	pathItem.Parameters = w.parameters(JoinJSONPointer(pointer, "parameters"), pathItem.Parameters)
+	operations := pathItem.Operations()
+	methods := make([]string, 0, len(operations))
+	for method := range operations {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		if w.operation(JoinJSONPointer(pointer, strings.ToLower(method)), method, operations[method]) {
+			pathItem.SetOperation(method, nil)
+		}
+	}
+	return false
+}
+
+func (w *walker) operation(pointer string, method string, operation *Operation) bool {
+	if operation == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnOperation; f != nil {
+		descend, remove = w.apply(f(pointer, method, operation))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(operation) {
+		return false
+	}
+	operation.Parameters = w.parameters(JoinJSONPointer(pointer, "parameters"), operation.Parameters)
+	if w.requestBody(JoinJSONPointer(pointer, "requestBody"), operation.RequestBody) {
+		operation.RequestBody = nil
+	}
+	w.responses(JoinJSONPointer(pointer, "responses"), operation.Responses)
+	w.callbacks(JoinJSONPointer(pointer, "callbacks"), operation.Callbacks)
+	return false
+}
+
+func (w *walker) parameters(pointer string, parameters Parameters) Parameters {
+	kept := parameters[:0:0]
+	for i, ref := range parameters {
+		if !w.parameter(JoinJSONPointer(pointer, strconv.Itoa(i)), ref) {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(parameters) {
+		return parameters
+	}
+	return kept
+}
+
+func (w *walker) parameter(pointer string, ref *ParameterRef) bool {
+	if ref == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnParameter; f != nil {
+		descend, remove = w.apply(f(pointer, ref))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(ref.Value) {
+		return false
+	}
+	w.parameterValue(pointer, ref.Value)
+	return false
+}
+
+// parameterValue walks the children of a parameter or header.
+func (w *walker) parameterValue(pointer string, parameter *Parameter) {
+	if w.schema(JoinJSONPointer(pointer, "schema"), parameter.Schema) {
+		parameter.Schema = nil
+	}
+	w.examples(JoinJSONPointer(pointer, "examples"), parameter.Examples)
+	w.content(JoinJSONPointer(pointer, "content"), parameter.Content)
+}
+
+func (w *walker) headers(pointer string, headers Headers) {
+	for _, name := range sortedMapKeys(reflect.ValueOf(headers)) {
+		if w.header(JoinJSONPointer(pointer, name), headers[name]) {
+			delete(headers, name)
+		}
+	}
+}
+
+func (w *walker) header(pointer string, ref *HeaderRef) bool {
+	if ref == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnHeader; f != nil {
+		descend, remove = w.apply(f(pointer, ref))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(ref.Value) {
+		return false
+	}
+	w.parameterValue(pointer, &ref.Value.Parameter)
+	return false
+}
+
+func (w *walker) requestBody(pointer string, ref *RequestBodyRef) bool {
+	if ref == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnRequestBody; f != nil {
+		descend, remove = w.apply(f(pointer, ref))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(ref.Value) {
+		return false
+	}
+	w.content(JoinJSONPointer(pointer, "content"), ref.Value.Content)
+	return false
+}
+
+func (w *walker) responses(pointer string, responses Responses) {
+	for _, status := range sortedMapKeys(reflect.ValueOf(responses)) {
+		if w.response(JoinJSONPointer(pointer, status), responses[status]) {
+			delete(responses, status)
+		}
+	}
+}
+
+func (w *walker) response(pointer string, ref *ResponseRef) bool {
+	if ref == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnResponse; f != nil {
+		descend, remove = w.apply(f(pointer, ref))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(ref.Value) {
+		return false
+	}
+	w.headers(JoinJSONPointer(pointer, "headers"), ref.Value.Headers)
+	w.content(JoinJSONPointer(pointer, "content"), ref.Value.Content)
+	w.links(JoinJSONPointer(pointer, "links"), ref.Value.Links)
+	return false
+}
+
+func (w *walker) content(pointer string, content Content) {
+	for _, mime := range sortedMapKeys(reflect.ValueOf(content)) {
+		if w.mediaType(JoinJSONPointer(pointer, mime), content[mime]) {
+			delete(content, mime)
+		}
+	}
+}
+
+func (w *walker) mediaType(pointer string, mediaType *MediaType) bool {
+	if mediaType == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnMediaType; f != nil {
+		descend, remove = w.apply(f(pointer, mediaType))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(mediaType) {
+		return false
+	}
+	if w.schema(JoinJSONPointer(pointer, "schema"), mediaType.Schema) {
+		mediaType.Schema = nil
+	}
+	w.examples(JoinJSONPointer(pointer, "examples"), mediaType.Examples)
+	for _, name := range sortedMapKeys(reflect.ValueOf(mediaType.Encoding)) {
+		if encoding := mediaType.Encoding[name]; encoding != nil {
+			w.headers(JoinJSONPointer(pointer, "encoding", name, "headers"), encoding.Headers)
+		}
+	}
+	return false
+}
+
+func (w *walker) schemas(pointer string, schemas Schemas) {
+	for _, name := range sortedMapKeys(reflect.ValueOf(schemas)) {
+		if w.schema(JoinJSONPointer(pointer, name), schemas[name]) {
+			delete(schemas, name)
+		}
+	}
+}
+
+func (w *walker) schemaRefs(pointer string, refs SchemaRefs) SchemaRefs {
+	kept := refs[:0:0]
+	for i, ref := range refs {
+		if !w.schema(JoinJSONPointer(pointer, strconv.Itoa(i)), ref) {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(refs) {
+		return refs
+	}
+	return kept
+}
+
+func (w *walker) schema(pointer string, ref *SchemaRef) bool {
+	if ref == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnSchema; f != nil {
+		descend, remove = w.apply(f(pointer, ref))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(ref.Value) {
+		return false
+	}
+	schema := ref.Value
+	// Definitions first, to walk them where they are defined
+	w.schemas(JoinJSONPointer(pointer, "$defs"), schema.Defs)
+	w.schemas(JoinJSONPointer(pointer, "properties"), schema.Properties)
+	for _, field := range []struct {
+		name string
+		ref  **SchemaRef
+	}{
+		{"items", &schema.Items},
+		{"additionalProperties", &schema.AdditionalProperties},
+		{"not", &schema.Not},
+		{"if", &schema.If},
+		{"then", &schema.Then},
+		{"else", &schema.Else},
+		{"unevaluatedProperties", &schema.UnevaluatedProperties},
+	} {
+		if w.schema(JoinJSONPointer(pointer, field.name), *field.ref) {
+			*field.ref = nil
+		}
+	}
+	schema.AllOf = w.schemaRefs(JoinJSONPointer(pointer, "allOf"), schema.AllOf)
+	schema.AnyOf = w.schemaRefs(JoinJSONPointer(pointer, "anyOf"), schema.AnyOf)
+	schema.OneOf = w.schemaRefs(JoinJSONPointer(pointer, "oneOf"), schema.OneOf)
+	schema.PrefixItems = w.schemaRefs(JoinJSONPointer(pointer, "prefixItems"), schema.PrefixItems)
+	w.schemas(JoinJSONPointer(pointer, "dependentSchemas"), schema.DependentSchemas)
+	return false
+}
+
+func (w *walker) examples(pointer string, examples Examples) {
+	for _, name := range sortedMapKeys(reflect.ValueOf(examples)) {
+		ref := examples[name]
+		if ref == nil || w.stopped || w.visitor.OnExample == nil {
+			continue
+		}
+		if _, remove := w.apply(w.visitor.OnExample(JoinJSONPointer(pointer, name), ref)); remove {
+			delete(examples, name)
+		}
+	}
+}
+
+func (w *walker) links(pointer string, links Links) {
+	for _, name := range sortedMapKeys(reflect.ValueOf(links)) {
+		ref := links[name]
+		if ref == nil || w.stopped || w.visitor.OnLink == nil {
+			continue
+		}
+		if _, remove := w.apply(w.visitor.OnLink(JoinJSONPointer(pointer, name), ref)); remove {
+			delete(links, name)
+		}
+	}
+}
+
+func (w *walker) callbacks(pointer string, callbacks Callbacks) {
+	for _, name := range sortedMapKeys(reflect.ValueOf(callbacks)) {
+		if w.callback(JoinJSONPointer(pointer, name), callbacks[name]) {
+			delete(callbacks, name)
+		}
+	}
+}
+
+func (w *walker) callback(pointer string, ref *CallbackRef) bool {
+	if ref == nil || w.stopped {
+		return false
+	}
+	descend, remove := true, false
+	if f := w.visitor.OnCallback; f != nil {
+		descend, remove = w.apply(f(pointer, ref))
+	}
+	if remove {
+		return true
+	}
+	if !descend || !w.enter(ref.Value) {
+		return false
+	}
+	callback := *ref.Value
+	for _, expression := range sortedMapKeys(reflect.ValueOf(callback)) {
+		if w.pathItem(JoinJSONPointer(pointer, expression), callback[expression]) {
+			delete(callback, expression)
+		}
+	}
+	return false
+}
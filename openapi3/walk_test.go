@@ -0,0 +1,181 @@
+package openapi3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const walkSpec = `
+openapi: 3.0.0
+info:
+  title: Walk
+  version: 1.0.0
+paths:
+  /nodes/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema:
+        type: string
+    get:
+      responses:
+        '200':
+          description: A node
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Node'
+    delete:
+      deprecated: true
+      parameters:
+      - name: force
+        in: query
+        schema:
+          type: boolean
+      responses:
+        '204':
+          description: Deleted
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+        internal:
+          type: string
+`
+
+func TestWalk(t *testing.T) {
+	doc, err := NewLoader().LoadFromData([]byte(walkSpec))
+	require.NoError(t, err)
+
+	var visited []string
+	record := func(pointer string) WalkAction {
+		visited = append(visited, pointer)
+		return WalkContinue
+	}
+	doc.Walk(&Visitor{
+		OnPathItem: func(pointer string, pathItem *PathItem) WalkAction { return record(pointer) },
+		OnOperation: func(pointer string, method string, operation *Operation) WalkAction {
+			return record(pointer)
+		},
+		OnParameter:   func(pointer string, parameter *ParameterRef) WalkAction { return record(pointer) },
+		OnResponse:    func(pointer string, response *ResponseRef) WalkAction { return record(pointer) },
+		OnMediaType:   func(pointer string, mediaType *MediaType) WalkAction { return record(pointer) },
+		OnSchema:      func(pointer string, schema *SchemaRef) WalkAction { return record(pointer) },
+		OnRequestBody: func(pointer string, requestBody *RequestBodyRef) WalkAction { return record(pointer) },
+	})
+	require.Equal(t, []string{
+		"/components/schemas/Node",
+		"/components/schemas/Node/properties/children",
+		// The recursive reference is visited, but not walked again
+		"/components/schemas/Node/properties/children/items",
+		"/components/schemas/Node/properties/internal",
+		"/paths/~1nodes~1{id}",
+		"/paths/~1nodes~1{id}/parameters/0",
+		"/paths/~1nodes~1{id}/parameters/0/schema",
+		"/paths/~1nodes~1{id}/delete",
+		"/paths/~1nodes~1{id}/delete/parameters/0",
+		"/paths/~1nodes~1{id}/delete/parameters/0/schema",
+		"/paths/~1nodes~1{id}/delete/responses/204",
+		"/paths/~1nodes~1{id}/get",
+		"/paths/~1nodes~1{id}/get/responses/200",
+		"/paths/~1nodes~1{id}/get/responses/200/content/application~1json",
+		"/paths/~1nodes~1{id}/get/responses/200/content/application~1json/schema",
+	}, visited)
+}
+
+func TestWalkDefs(t *testing.T) {
+	doc, err := NewLoader().LoadFromData([]byte(`
+openapi: 3.1.0
+info:
+  title: Walk
+  version: 1.0.0
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        age:
+          $ref: '#/components/schemas/Pet/$defs/Age'
+      $defs:
+        Age:
+          type: integer
+          not:
+            const: 0
+`))
+	require.NoError(t, err)
+
+	var visited []string
+	doc.Walk(&Visitor{
+		OnSchema: func(pointer string, schema *SchemaRef) WalkAction {
+			visited = append(visited, pointer)
+			return WalkContinue
+		},
+	})
+	require.Equal(t, []string{
+		"/components/schemas/Pet",
+		"/components/schemas/Pet/$defs/Age",
+		"/components/schemas/Pet/$defs/Age/not",
+		"/components/schemas/Pet/properties/age",
+	}, visited)
+}
+
+func TestWalkPruneAndReplace(t *testing.T) {
+	doc, err := NewLoader().LoadFromData([]byte(walkSpec))
+	require.NoError(t, err)
+
+	var visited []string
+	doc.Walk(&Visitor{
+		OnOperation: func(pointer string, method string, operation *Operation) WalkAction {
+			if operation.Deprecated {
+				return WalkRemove
+			}
+			return WalkContinue
+		},
+		OnParameter: func(pointer string, parameter *ParameterRef) WalkAction {
+			visited = append(visited, pointer)
+			return WalkSkip
+		},
+		OnSchema: func(pointer string, schema *SchemaRef) WalkAction {
+			visited = append(visited, pointer)
+			if pointer == "/components/schemas/Node/properties/internal" {
+				return WalkRemove
+			}
+			if schema.Ref == "" && schema.Value.Type == "array" {
+				// Replace the array of nodes by a list of their IDs
+				schema.Value = NewArraySchema().WithItems(NewStringSchema())
+				return WalkSkip
+			}
+			return WalkContinue
+		},
+	})
+	require.Equal(t, []string{
+		"/components/schemas/Node",
+		"/components/schemas/Node/properties/children",
+		"/components/schemas/Node/properties/internal",
+		"/paths/~1nodes~1{id}/parameters/0",
+		"/paths/~1nodes~1{id}/get/responses/200/content/application~1json/schema",
+	}, visited)
+
+	pathItem := doc.Paths["/nodes/{id}"]
+	require.Nil(t, pathItem.Delete)
+	require.NotNil(t, pathItem.Get)
+	node := doc.Components.Schemas["Node"].Value
+	require.NotContains(t, node.Properties, "internal")
+	require.Equal(t, "string", node.Properties["children"].Value.Items.Value.Type)
+
+	visited = nil
+	doc.Walk(&Visitor{
+		OnSchema: func(pointer string, schema *SchemaRef) WalkAction {
+			visited = append(visited, pointer)
+			return WalkStop
+		},
+	})
+	require.Equal(t, []string{"/components/schemas/Node"}, visited)
+}
@@ -0,0 +1,35 @@
+package openapi3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Webhooks is specified by OpenAPI/Swagger standard version 3.1.
+// It maps webhook names to the requests the API provider may initiate.
+type Webhooks map[string]*PathItem
+
+func (value Webhooks) Validate(ctx context.Context) error {
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pathItem := value[name]
+		if err := validateAt(ctx, func(ctx context.Context) error {
+			if pathItem == nil {
+				return fmt.Errorf("webhook %q: value MUST be an object", name)
+			}
+			if err := pathItem.Validate(ctx); err != nil {
+				return fmt.Errorf("webhook %q: %v", name, err)
+			}
+			return nil
+		}, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
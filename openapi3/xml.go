@@ -0,0 +1,38 @@
+package openapi3
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/jsoninfo"
+)
+
+// XML is specified by OpenAPI/Swagger standard version 3.0.
+type XML struct {
+	ExtensionProps
+
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Prefix    string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Attribute bool   `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Wrapped   bool   `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
+}
+
+func (value *XML) MarshalJSON() ([]byte, error) {
+	return jsoninfo.MarshalStrictStruct(value)
+}
+
+func (value *XML) UnmarshalJSON(data []byte) error {
+	return jsoninfo.UnmarshalStrictStruct(data, value)
+}
+
+// Validate returns an error if XML does not comply with the OpenAPI spec.
+func (value *XML) Validate(ctx context.Context) error {
+	if value.Namespace != "" {
+		if u, err := url.Parse(value.Namespace); err != nil || !u.IsAbs() {
+			return fmt.Errorf("xml namespace %q must be an absolute URI", value.Namespace)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package openapi3
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaXML(t *testing.T) {
+	var schema Schema
+	err := json.Unmarshal([]byte(`{
+	"type": "array",
+	"items": {"type": "string", "xml": {"name": "photoUrl"}},
+	"xml": {"name": "photoUrls", "namespace": "https://example.com/schema", "prefix": "ex", "wrapped": true, "x-order": 1}
+}`), &schema)
+	require.NoError(t, err)
+	require.Equal(t, "photoUrls", schema.XML.Name)
+	require.Equal(t, "https://example.com/schema", schema.XML.Namespace)
+	require.Equal(t, "ex", schema.XML.Prefix)
+	require.True(t, schema.XML.Wrapped)
+	require.False(t, schema.XML.Attribute)
+	require.Contains(t, schema.XML.Extensions, "x-order")
+	require.Equal(t, "photoUrl", schema.Items.Value.XML.Name)
+	require.NoError(t, schema.Validate(context.Background()))
+
+	data, err := json.Marshal(schema.Items.Value)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "string", "xml": {"name": "photoUrl"}}`, string(data))
+
+	schema.XML.Namespace = "example"
+	err = schema.Validate(context.Background())
+	require.EqualError(t, err, `xml namespace "example" must be an absolute URI`)
+
+	schema.XML.Namespace = ""
+	// wrapped is meaningless for attributes but allowed
+	schema.XML.Attribute = true
+	err = schema.Validate(context.Background())
+	require.NoError(t, err)
+}
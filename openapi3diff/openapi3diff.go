@@ -0,0 +1,462 @@
+// Package openapi3diff compares two OpenAPIv3 documents and classifies
+// their differences as breaking or not for existing clients.
+//
+// Documents are expected to be loaded with their references resolved:
+// schemas, parameters, request bodies and responses are compared by value.
+package openapi3diff
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ChangeKind tells whether an element was added, removed or changed.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change describes one difference between two documents.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Breaking is set when clients of the base document may fail
+	// against the revised one.
+	Breaking bool `json:"breaking"`
+	// Path and Method identify the operation, if any, the change is about.
+	Path   string `json:"path,omitempty"`
+	Method string `json:"method,omitempty"`
+	// Location is a dotted path to the changed element within the operation
+	// (e.g. "requestBody.content.application/json.schema.properties.name").
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (change *Change) String() string {
+	var sb strings.Builder
+	if change.Breaking {
+		sb.WriteString("[breaking] ")
+	}
+	if change.Method != "" {
+		sb.WriteString(change.Method + " ")
+	}
+	if change.Path != "" {
+		sb.WriteString(change.Path + " ")
+	}
+	if change.Location != "" {
+		sb.WriteString(change.Location + ": ")
+	}
+	sb.WriteString(change.Message)
+	return sb.String()
+}
+
+// Report lists the changes between two documents.
+// It marshals to JSON for consumption by CI jobs.
+type Report struct {
+	// Breaking is set when at least one change is breaking.
+	Breaking bool      `json:"breaking"`
+	Changes  []*Change `json:"changes"`
+}
+
+// BreakingChanges returns the breaking changes of the report.
+func (report *Report) BreakingChanges() []*Change {
+	var changes []*Change
+	for _, change := range report.Changes {
+		if change.Breaking {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Diff compares base to revision.
+func Diff(base, revision *openapi3.T) *Report {
+	d := &differ{visited: make(map[[2]*openapi3.Schema]struct{})}
+	d.diffPaths(base, revision)
+	report := &Report{Changes: d.changes}
+	report.Breaking = len(report.BreakingChanges()) != 0
+	return report
+}
+
+// direction tells which side of an exchange a schema describes:
+// requests are sent by clients, responses are received by them.
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type differ struct {
+	changes []*Change
+
+	// current operation
+	path, method string
+
+	// schema pairs being compared, to stop at recursive schemas
+	visited map[[2]*openapi3.Schema]struct{}
+}
+
+func (d *differ) add(kind ChangeKind, breaking bool, location string, format string, args ...interface{}) {
+	d.changes = append(d.changes, &Change{
+		Kind:     kind,
+		Breaking: breaking,
+		Path:     d.path,
+		Method:   d.method,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func join(location, name string) string {
+	if location == "" {
+		return name
+	}
+	return location + "." + name
+}
+
+var methods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
+// diffPaths pairs the paths of both documents by their templates with variables unnamed,
+// so that renaming a path parameter is not taken for the removal of a path.
+func (d *differ) diffPaths(base, revision *openapi3.T) {
+	basePaths, revisionPaths := normalizedPaths(base.Paths), normalizedPaths(revision.Paths)
+	for _, key := range unionKeys(pathNames(basePaths), pathNames(revisionPaths)) {
+		basePath, revisionPath := basePaths[key], revisionPaths[key]
+		d.method = ""
+		switch {
+		case revisionPath == "":
+			d.path = basePath
+			d.add(Removed, true, "", "path removed")
+			continue
+		case basePath == "":
+			d.path = revisionPath
+			d.add(Added, false, "", "path added")
+			continue
+		}
+		d.path = revisionPath
+		if basePath != revisionPath {
+			d.add(Changed, false, "", "path parameters renamed from %s", basePath)
+		}
+		renames := variableRenames(basePath, revisionPath)
+		baseItem, revisionItem := base.Paths[basePath], revision.Paths[revisionPath]
+		for _, method := range methods {
+			d.method = method
+			baseOp, revisionOp := baseItem.GetOperation(method), revisionItem.GetOperation(method)
+			switch {
+			case baseOp == nil && revisionOp == nil:
+			case revisionOp == nil:
+				d.add(Removed, true, "", "operation removed")
+			case baseOp == nil:
+				d.add(Added, false, "", "operation added")
+			default:
+				d.diffOperation(base, revision, baseItem, revisionItem, baseOp, revisionOp, renames)
+			}
+		}
+	}
+	d.path, d.method = "", ""
+}
+
+func (d *differ) diffOperation(base, revision *openapi3.T, baseItem, revisionItem *openapi3.PathItem,
+	baseOp, revisionOp *openapi3.Operation, renames map[string]string) {
+	if !baseOp.Deprecated && revisionOp.Deprecated {
+		d.add(Changed, false, "deprecated", "operation deprecated")
+	}
+	d.diffParameters(parameters(baseItem, baseOp, nil), parameters(revisionItem, revisionOp, renames))
+	d.diffRequestBody(baseOp.RequestBody, revisionOp.RequestBody)
+	d.diffResponses(baseOp.Responses, revisionOp.Responses)
+	d.diffSecurity(security(base, baseOp), security(revision, revisionOp))
+}
+
+// parameters returns the parameters of an operation, including those of its path item,
+// keyed by location and name: the base name of renamed path parameters,
+// the canonical form of header names.
+func parameters(item *openapi3.PathItem, op *openapi3.Operation, renames map[string]string) map[string]*openapi3.Parameter {
+	params := make(map[string]*openapi3.Parameter)
+	for _, refs := range []openapi3.Parameters{item.Parameters, op.Parameters} {
+		for _, ref := range refs {
+			p := ref.Value
+			if p == nil {
+				continue
+			}
+			name := p.Name
+			switch p.In {
+			case openapi3.ParameterInPath:
+				if baseName, ok := renames[name]; ok {
+					name = baseName
+				}
+			case openapi3.ParameterInHeader:
+				name = http.CanonicalHeaderKey(name)
+			}
+			params[p.In+"."+name] = p
+		}
+	}
+	return params
+}
+
+var templateVariable = regexp.MustCompile(`\{[^{}]*\}`)
+
+// normalizedPaths returns the templates of paths by their form with variables unnamed,
+// such as /users/{} for /users/{id}.
+func normalizedPaths(paths openapi3.Paths) map[string]string {
+	normalized := make(map[string]string, len(paths))
+	for path := range paths {
+		normalized[templateVariable.ReplaceAllString(path, "{}")] = path
+	}
+	return normalized
+}
+
+// variableRenames returns the base name of the variables of revision
+// named differently in base, two templates of the same normalized form.
+func variableRenames(base, revision string) map[string]string {
+	baseNames := templateVariable.FindAllString(base, -1)
+	revisionNames := templateVariable.FindAllString(revision, -1)
+	var renames map[string]string
+	for i, name := range revisionNames {
+		if i < len(baseNames) && baseNames[i] != name {
+			if renames == nil {
+				renames = make(map[string]string)
+			}
+			renames[strings.Trim(name, "{}")] = strings.Trim(baseNames[i], "{}")
+		}
+	}
+	return renames
+}
+
+func (d *differ) diffParameters(base, revision map[string]*openapi3.Parameter) {
+	for _, key := range unionKeys(parameterKeys(base), parameterKeys(revision)) {
+		location := "parameters." + key
+		b, r := base[key], revision[key]
+		switch {
+		case r == nil:
+			d.add(Removed, b.In == openapi3.ParameterInPath, location, "parameter removed")
+		case b == nil:
+			if r.Required {
+				d.add(Added, true, location, "required parameter added")
+			} else {
+				d.add(Added, false, location, "optional parameter added")
+			}
+		default:
+			if !b.Required && r.Required {
+				d.add(Changed, true, location, "parameter became required")
+			} else if b.Required && !r.Required {
+				d.add(Changed, false, location, "parameter became optional")
+			}
+			if b.Style != r.Style || explode(b) != explode(r) {
+				d.add(Changed, true, location, "serialization changed")
+			}
+			d.diffSchemaRefs(request, join(location, "schema"), b.Schema, r.Schema)
+			d.diffContent(request, join(location, "content"), b.Content, r.Content)
+		}
+	}
+}
+
+func explode(p *openapi3.Parameter) bool {
+	sm, err := p.SerializationMethod()
+	return err == nil && sm.Explode
+}
+
+func (d *differ) diffRequestBody(base, revision *openapi3.RequestBodyRef) {
+	const location = "requestBody"
+	var b, r *openapi3.RequestBody
+	if base != nil {
+		b = base.Value
+	}
+	if revision != nil {
+		r = revision.Value
+	}
+	switch {
+	case b == nil && r == nil:
+		return
+	case r == nil:
+		d.add(Removed, false, location, "request body removed")
+		return
+	case b == nil:
+		d.add(Added, r.Required, location, "request body added")
+		return
+	}
+	if !b.Required && r.Required {
+		d.add(Changed, true, location, "request body became required")
+	} else if b.Required && !r.Required {
+		d.add(Changed, false, location, "request body became optional")
+	}
+	d.diffContent(request, join(location, "content"), b.Content, r.Content)
+}
+
+func (d *differ) diffResponses(base, revision openapi3.Responses) {
+	for _, status := range unionKeys(responseKeys(base), responseKeys(revision)) {
+		location := "responses." + status
+		var b, r *openapi3.Response
+		if ref := base[status]; ref != nil {
+			b = ref.Value
+		}
+		if ref := revision[status]; ref != nil {
+			r = ref.Value
+		}
+		switch {
+		case b == nil && r == nil:
+			continue
+		case r == nil:
+			d.add(Removed, strings.HasPrefix(status, "2"), location, "response removed")
+			continue
+		case b == nil:
+			d.add(Added, false, location, "response added")
+			continue
+		}
+		for _, name := range unionKeys(headerKeys(b.Headers), headerKeys(r.Headers)) {
+			headerLocation := join(location, "headers."+name)
+			bh, rh := b.Headers[name], r.Headers[name]
+			switch {
+			case rh == nil:
+				d.add(Removed, true, headerLocation, "response header removed")
+			case bh == nil:
+				d.add(Added, false, headerLocation, "response header added")
+			case bh.Value != nil && rh.Value != nil:
+				if bh.Value.Required && !rh.Value.Required {
+					d.add(Changed, true, headerLocation, "response header became optional")
+				}
+				d.diffSchemaRefs(response, join(headerLocation, "schema"), bh.Value.Schema, rh.Value.Schema)
+			}
+		}
+		d.diffContent(response, join(location, "content"), b.Content, r.Content)
+	}
+}
+
+func (d *differ) diffContent(dir direction, location string, base, revision openapi3.Content) {
+	for _, mime := range unionKeys(contentKeys(base), contentKeys(revision)) {
+		mimeLocation := join(location, mime)
+		b, r := base[mime], revision[mime]
+		switch {
+		case r == nil:
+			d.add(Removed, true, mimeLocation, "media type removed")
+		case b == nil:
+			d.add(Added, false, mimeLocation, "media type added")
+		default:
+			d.diffSchemaRefs(dir, join(mimeLocation, "schema"), b.Schema, r.Schema)
+		}
+	}
+}
+
+// security returns the alternative requirements an operation accepts,
+// each as a sorted list of "scheme:scope,..." entries.
+// An operation without requirements accepts the anonymous alternative "".
+func security(doc *openapi3.T, op *openapi3.Operation) []string {
+	requirements := doc.Security
+	if op.Security != nil {
+		requirements = *op.Security
+	}
+	if len(requirements) == 0 {
+		return []string{""}
+	}
+	alternatives := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		entries := make([]string, 0, len(requirement))
+		for name, scopes := range requirement {
+			scopes = append([]string(nil), scopes...)
+			sort.Strings(scopes)
+			entries = append(entries, name+":"+strings.Join(scopes, ","))
+		}
+		sort.Strings(entries)
+		alternatives = append(alternatives, strings.Join(entries, " "))
+	}
+	return alternatives
+}
+
+func (d *differ) diffSecurity(base, revision []string) {
+	for _, alternative := range unionKeys(base, revision) {
+		b, r := contains(base, alternative), contains(revision, alternative)
+		name := alternative
+		if name == "" {
+			name = "anonymous access"
+		}
+		switch {
+		case b && !r:
+			d.add(Removed, true, "security", "security requirement %q removed", name)
+		case !b && r:
+			d.add(Added, false, "security", "security requirement %q added", name)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// unionKeys returns the sorted union of two sets of keys.
+func unionKeys(a, b []string) []string {
+	set := make(map[string]struct{}, len(a)+len(b))
+	for _, k := range a {
+		set[k] = struct{}{}
+	}
+	for _, k := range b {
+		set[k] = struct{}{}
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func pathNames(paths map[string]string) []string {
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func parameterKeys(params map[string]*openapi3.Parameter) []string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func responseKeys(responses openapi3.Responses) []string {
+	keys := make([]string, 0, len(responses))
+	for k := range responses {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func headerKeys(headers openapi3.Headers) []string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func contentKeys(content openapi3.Content) []string {
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	return keys
+}
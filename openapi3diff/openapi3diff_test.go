@@ -0,0 +1,377 @@
+package openapi3diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const baseSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+security:
+- apiKey: []
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          maximum: 100
+      - name: kind
+        in: query
+        schema:
+          type: string
+          enum: [cat, dog]
+      responses:
+        '200':
+          description: Pets
+          headers:
+            X-Total:
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        '201':
+          description: Created
+  /pets/{id}:
+    delete:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      responses:
+        '204':
+          description: Deleted
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+        parent:
+          $ref: '#/components/schemas/Pet'
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          maxLength: 50
+        tag:
+          type: string
+`
+
+const revisionSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 2.0.0
+security:
+- apiKey: []
+- {}
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          maximum: 50
+      - name: kind
+        in: query
+        schema:
+          type: string
+          enum: [cat, dog, bird]
+      - name: owner
+        in: query
+        required: true
+        schema:
+          type: string
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+            application/xml:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        '201':
+          description: Created
+  /owners:
+    get:
+      responses:
+        '200':
+          description: Owners
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        age:
+          type: integer
+        parent:
+          $ref: '#/components/schemas/Pet'
+    NewPet:
+      type: object
+      required: [name, age]
+      properties:
+        name:
+          type: string
+          maxLength: 100
+        tag:
+          type: string
+        age:
+          type: integer
+`
+
+func load(t *testing.T, spec string) *openapi3.T {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestDiff(t *testing.T) {
+	base, revision := load(t, baseSpec), load(t, revisionSpec)
+
+	report := Diff(base, revision)
+	require.True(t, report.Breaking)
+
+	var changes []string
+	for _, change := range report.Changes {
+		changes = append(changes, change.String())
+	}
+	require.Equal(t, []string{
+		"/owners path added",
+		"GET /pets parameters.query.kind.schema.enum: enum value bird added",
+		"[breaking] GET /pets parameters.query.limit.schema.maximum: changed from 100 to 50",
+		"[breaking] GET /pets parameters.query.owner: required parameter added",
+		"[breaking] GET /pets responses.200.headers.X-Total: response header removed",
+		"GET /pets responses.200.content.application/json.schema.items.properties.age: property added",
+		"[breaking] GET /pets responses.200.content.application/json.schema.items.properties.tag: property removed",
+		"GET /pets responses.200.content.application/xml: media type added",
+		`GET /pets security: security requirement "anonymous access" added`,
+		"[breaking] POST /pets requestBody.content.application/json.schema.properties.age: property added",
+		"POST /pets requestBody.content.application/json.schema.properties.name.maxLength: changed from 50 to 100",
+		`POST /pets security: security requirement "anonymous access" added`,
+		"[breaking] /pets/{id} path removed",
+	}, changes)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded struct {
+		Breaking bool `json:"breaking"`
+		Changes  []struct {
+			Kind     string `json:"kind"`
+			Breaking bool   `json:"breaking"`
+			Path     string `json:"path"`
+			Method   string `json:"method"`
+			Location string `json:"location"`
+		} `json:"changes"`
+	}
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+	require.True(t, decoded.Breaking)
+	require.Len(t, decoded.Changes, len(changes))
+	require.Equal(t, "removed", decoded.Changes[4].Kind)
+	require.Equal(t, "/pets", decoded.Changes[4].Path)
+	require.Equal(t, "GET", decoded.Changes[4].Method)
+	require.Equal(t, "responses.200.headers.X-Total", decoded.Changes[4].Location)
+	require.Len(t, report.BreakingChanges(), 6)
+}
+
+func TestDiffSame(t *testing.T) {
+	report := Diff(load(t, baseSpec), load(t, baseSpec))
+	require.False(t, report.Breaking)
+	require.Empty(t, report.Changes)
+}
+
+func TestDiffResponseDirection(t *testing.T) {
+	base := openapi3.NewStringSchema().WithEnum("a", "b")
+	revision := openapi3.NewStringSchema().WithEnum("a", "b", "c")
+
+	d := &differ{visited: make(map[[2]*openapi3.Schema]struct{})}
+	d.diffSchema(request, "", base, revision)
+	require.Len(t, d.changes, 1)
+	require.False(t, d.changes[0].Breaking)
+
+	d = &differ{visited: make(map[[2]*openapi3.Schema]struct{})}
+	d.diffSchema(response, "", base, revision)
+	require.Len(t, d.changes, 1)
+	require.True(t, d.changes[0].Breaking)
+}
+
+func TestDiffRenamedPathParameter(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /users/{%s}:
+    get:
+      parameters:
+      - name: %s
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: %s
+        in: header
+        required: %t
+        schema:
+          type: string
+      responses:
+        '200':
+          description: OK
+`
+	base := load(t, fmt.Sprintf(spec, "id", "id", "X-Request-Id", false))
+	revision := load(t, fmt.Sprintf(spec, "userId", "userId", "x-request-id", true))
+
+	var changes []string
+	for _, change := range Diff(base, revision).Changes {
+		changes = append(changes, change.String())
+	}
+	require.Equal(t, []string{
+		"/users/{userId} path parameters renamed from /users/{id}",
+		"[breaking] GET /users/{userId} parameters.header.X-Request-Id: parameter became required",
+	}, changes)
+}
+
+func diffSchemas(dir direction, base, revision *openapi3.Schema) []string {
+	d := &differ{visited: make(map[[2]*openapi3.Schema]struct{})}
+	d.diffSchema(dir, "schema", base, revision)
+	var changes []string
+	for _, change := range d.changes {
+		changes = append(changes, change.String())
+	}
+	return changes
+}
+
+func TestDiffOpenAPI3_1Keywords(t *testing.T) {
+	require.Equal(t, []string{
+		"[breaking] schema.exclusiveMinimum: changed from 5 to 10",
+	}, diffSchemas(request,
+		openapi3.NewIntegerSchema().WithExclusiveMinValue(5),
+		openapi3.NewIntegerSchema().WithExclusiveMinValue(10)))
+	require.Equal(t, []string{
+		"schema.exclusiveMaximum: changed from 5 to 10",
+	}, diffSchemas(request,
+		openapi3.NewIntegerSchema().WithExclusiveMaxValue(5),
+		openapi3.NewIntegerSchema().WithExclusiveMaxValue(10)))
+
+	require.Equal(t, []string{
+		"[breaking] schema.const: const added",
+	}, diffSchemas(request, openapi3.NewStringSchema(), openapi3.NewStringSchema().WithConst(nil)))
+	require.Equal(t, []string{
+		"[breaking] schema.const: changed from a to b",
+	}, diffSchemas(response, openapi3.NewStringSchema().WithConst("a"), openapi3.NewStringSchema().WithConst("b")))
+
+	base := openapi3.NewArraySchema()
+	base.PrefixItems = openapi3.SchemaRefs{openapi3.NewStringSchema().NewRef()}
+	revision := openapi3.NewArraySchema()
+	revision.PrefixItems = openapi3.SchemaRefs{openapi3.NewIntegerSchema().NewRef(), openapi3.NewStringSchema().NewRef()}
+	require.Equal(t, []string{
+		`[breaking] schema.prefixItems.0: type changed from "string" to "integer"`,
+		"[breaking] schema.prefixItems.1: schema added",
+	}, diffSchemas(request, base, revision))
+
+	base = openapi3.NewObjectSchema()
+	base.If = openapi3.NewObjectSchema().WithProperty("kind", openapi3.NewStringSchema().WithConst("cat")).NewRef()
+	base.Then = openapi3.NewObjectSchema().WithMaxProperties(2).NewRef()
+	base.DependentSchemas = openapi3.Schemas{"a": openapi3.NewObjectSchema().NewRef()}
+	revision = openapi3.NewObjectSchema()
+	revision.If = openapi3.NewObjectSchema().WithProperty("kind", openapi3.NewStringSchema().WithConst("dog")).NewRef()
+	revision.Then = openapi3.NewObjectSchema().WithMaxProperties(1).NewRef()
+	revision.DependentSchemas = openapi3.Schemas{"b": openapi3.NewObjectSchema().NewRef()}
+	require.Equal(t, []string{
+		"schema.dependentSchemas.a: dependent schema removed",
+		"[breaking] schema.dependentSchemas.b: dependent schema added",
+		"[breaking] schema.if: changed",
+		"[breaking] schema.then.maxProperties: changed from 2 to 1",
+	}, diffSchemas(request, base, revision))
+}
+
+func TestDiffSchemaListMembers(t *testing.T) {
+	pet := &openapi3.SchemaRef{Ref: "#/components/schemas/Pet", Value: openapi3.NewObjectSchema()}
+	owner := &openapi3.SchemaRef{Ref: "#/components/schemas/Owner", Value: openapi3.NewObjectSchema()}
+
+	base := openapi3.NewSchema()
+	base.AnyOf = openapi3.SchemaRefs{pet, openapi3.NewStringSchema().NewRef(), openapi3.NewIntegerSchema().WithMax(10).NewRef()}
+	revision := openapi3.NewSchema()
+	revision.AnyOf = openapi3.SchemaRefs{openapi3.NewIntegerSchema().WithMax(5).NewRef(), openapi3.NewStringSchema().NewRef(), pet}
+	require.Equal(t, []string{
+		"[breaking] schema.anyOf.2.maximum: changed from 10 to 5",
+	}, diffSchemas(request, base, revision))
+
+	base = openapi3.NewSchema()
+	base.AllOf = openapi3.SchemaRefs{pet}
+	revision = openapi3.NewSchema()
+	revision.AllOf = openapi3.SchemaRefs{owner, pet}
+	require.Equal(t, []string{
+		"[breaking] schema.allOf.0: schema added",
+	}, diffSchemas(request, base, revision))
+	require.Equal(t, []string{
+		"schema.allOf.0: schema removed",
+	}, diffSchemas(request, revision, base))
+}
@@ -0,0 +1,361 @@
+package openapi3diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func (d *differ) diffSchemaRefs(dir direction, location string, base, revision *openapi3.SchemaRef) {
+	var b, r *openapi3.Schema
+	if base != nil {
+		b = base.Value
+	}
+	if revision != nil {
+		r = revision.Value
+	}
+	switch {
+	case b == nil && r == nil:
+	case r == nil:
+		d.add(Removed, dir == response, location, "schema removed")
+	case b == nil:
+		d.add(Added, dir == request, location, "schema added")
+	default:
+		d.diffSchema(dir, location, b, r)
+	}
+}
+
+// diffSchema compares two schemas. Changes are breaking when they narrow
+// what a request may contain or widen what a response may contain.
+func (d *differ) diffSchema(dir direction, location string, base, revision *openapi3.Schema) {
+	pair := [2]*openapi3.Schema{base, revision}
+	if _, ok := d.visited[pair]; ok {
+		return
+	}
+	d.visited[pair] = struct{}{}
+	defer delete(d.visited, pair)
+
+	if bt, rt := typeOf(base), typeOf(revision); bt != rt {
+		d.add(Changed, true, location, "type changed from %q to %q", bt, rt)
+		return
+	}
+	if base.Format != revision.Format {
+		d.add(Changed, true, location, "format changed from %q to %q", base.Format, revision.Format)
+	}
+	if base.Nullable != revision.Nullable {
+		narrowed := base.Nullable
+		d.add(Changed, d.breaks(dir, narrowed), location, "nullable changed to %v", revision.Nullable)
+	}
+
+	d.diffEnum(dir, location, base.Enum, revision.Enum)
+	d.diffConst(dir, location, base, revision)
+
+	// Numbers
+	d.diffMin(dir, join(location, "minimum"), base.Min, revision.Min)
+	d.diffMax(dir, join(location, "maximum"), base.Max, revision.Max)
+	if base.ExclusiveMin != revision.ExclusiveMin {
+		d.add(Changed, d.breaks(dir, revision.ExclusiveMin), join(location, "exclusiveMinimum"), "changed to %v", revision.ExclusiveMin)
+	}
+	if base.ExclusiveMax != revision.ExclusiveMax {
+		d.add(Changed, d.breaks(dir, revision.ExclusiveMax), join(location, "exclusiveMaximum"), "changed to %v", revision.ExclusiveMax)
+	}
+	d.diffMin(dir, join(location, "exclusiveMinimum"), base.ExclusiveMinValue, revision.ExclusiveMinValue)
+	d.diffMax(dir, join(location, "exclusiveMaximum"), base.ExclusiveMaxValue, revision.ExclusiveMaxValue)
+	if !equalFloat64Ptr(base.MultipleOf, revision.MultipleOf) {
+		d.add(Changed, true, join(location, "multipleOf"), "changed from %s to %s", formatFloat64Ptr(base.MultipleOf), formatFloat64Ptr(revision.MultipleOf))
+	}
+
+	// Strings
+	d.diffMin(dir, join(location, "minLength"), uint64Ptr(base.MinLength), uint64Ptr(revision.MinLength))
+	d.diffMax(dir, join(location, "maxLength"), uint64PtrToFloat(base.MaxLength), uint64PtrToFloat(revision.MaxLength))
+	if base.Pattern != revision.Pattern {
+		d.add(Changed, true, join(location, "pattern"), "changed from %q to %q", base.Pattern, revision.Pattern)
+	}
+
+	// Arrays
+	d.diffMin(dir, join(location, "minItems"), uint64Ptr(base.MinItems), uint64Ptr(revision.MinItems))
+	d.diffMax(dir, join(location, "maxItems"), uint64PtrToFloat(base.MaxItems), uint64PtrToFloat(revision.MaxItems))
+	if base.UniqueItems != revision.UniqueItems {
+		d.add(Changed, d.breaks(dir, revision.UniqueItems), join(location, "uniqueItems"), "changed to %v", revision.UniqueItems)
+	}
+	d.diffSchemaRefs(dir, join(location, "items"), base.Items, revision.Items)
+	for i := 0; i < len(base.PrefixItems) || i < len(revision.PrefixItems); i++ {
+		var b, r *openapi3.SchemaRef
+		if i < len(base.PrefixItems) {
+			b = base.PrefixItems[i]
+		}
+		if i < len(revision.PrefixItems) {
+			r = revision.PrefixItems[i]
+		}
+		d.diffSchemaRefs(dir, fmt.Sprintf("%s.%d", join(location, "prefixItems"), i), b, r)
+	}
+
+	// Objects
+	d.diffProperties(dir, location, base, revision)
+	d.diffMin(dir, join(location, "minProperties"), uint64Ptr(base.MinProps), uint64Ptr(revision.MinProps))
+	d.diffMax(dir, join(location, "maxProperties"), uint64PtrToFloat(base.MaxProps), uint64PtrToFloat(revision.MaxProps))
+	if ba, ra := allowsAdditionalProperties(base), allowsAdditionalProperties(revision); ba != ra {
+		d.add(Changed, d.breaks(dir, ba), join(location, "additionalProperties"), "changed to %v", ra)
+	}
+	if base.AdditionalProperties != nil && revision.AdditionalProperties != nil {
+		d.diffSchemaRefs(dir, join(location, "additionalProperties"), base.AdditionalProperties, revision.AdditionalProperties)
+	}
+	d.diffDependentSchemas(dir, join(location, "dependentSchemas"), base.DependentSchemas, revision.DependentSchemas)
+
+	// Composition
+	d.diffSchemaList(dir, join(location, "allOf"), base.AllOf, revision.AllOf, true)
+	d.diffSchemaList(dir, join(location, "anyOf"), base.AnyOf, revision.AnyOf, false)
+	d.diffSchemaList(dir, join(location, "oneOf"), base.OneOf, revision.OneOf, false)
+	if (base.Not == nil) != (revision.Not == nil) {
+		d.add(Changed, true, join(location, "not"), "changed")
+	} else if base.Not != nil {
+		// Narrowing "not" widens the schema
+		d.diffSchemaRefs(1-dir, join(location, "not"), base.Not, revision.Not)
+	}
+
+	// Conditions: a changed "if" moves values between "then" and "else"
+	if d.differs(base.If, revision.If) {
+		d.add(Changed, true, join(location, "if"), "changed")
+	}
+	d.diffSchemaRefs(dir, join(location, "then"), base.Then, revision.Then)
+	d.diffSchemaRefs(dir, join(location, "else"), base.Else, revision.Else)
+}
+
+// differs returns whether comparing two schemas finds any change.
+func (d *differ) differs(base, revision *openapi3.SchemaRef) bool {
+	scratch := &differ{path: d.path, method: d.method, visited: d.visited}
+	scratch.diffSchemaRefs(request, "", base, revision)
+	return len(scratch.changes) != 0
+}
+
+func (d *differ) diffConst(dir direction, location string, base, revision *openapi3.Schema) {
+	location = join(location, "const")
+	switch bc, rc := base.HasConst(), revision.HasConst(); {
+	case !bc && !rc:
+	case !rc:
+		d.add(Removed, d.breaks(dir, false), location, "const removed")
+	case !bc:
+		d.add(Added, d.breaks(dir, true), location, "const added")
+	case fmt.Sprint(base.Const) != fmt.Sprint(revision.Const):
+		d.add(Changed, true, location, "changed from %v to %v", base.Const, revision.Const)
+	}
+}
+
+func (d *differ) diffDependentSchemas(dir direction, location string, base, revision openapi3.Schemas) {
+	names := make([]string, 0, len(base)+len(revision))
+	for name := range base {
+		names = append(names, name)
+	}
+	for name := range revision {
+		if _, ok := base[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, r := base[name], revision[name]
+		switch {
+		case r == nil:
+			d.add(Removed, d.breaks(dir, false), join(location, name), "dependent schema removed")
+		case b == nil:
+			d.add(Added, d.breaks(dir, true), join(location, name), "dependent schema added")
+		default:
+			d.diffSchemaRefs(dir, join(location, name), b, r)
+		}
+	}
+}
+
+// breaks returns whether a change that narrowed the accepted values
+// (or widened them when narrowed is false) breaks clients.
+func (d *differ) breaks(dir direction, narrowed bool) bool {
+	if dir == request {
+		return narrowed
+	}
+	return !narrowed
+}
+
+func (d *differ) diffEnum(dir direction, location string, base, revision []interface{}) {
+	location = join(location, "enum")
+	switch {
+	case len(base) == 0 && len(revision) == 0:
+		return
+	case len(revision) == 0:
+		d.add(Removed, d.breaks(dir, false), location, "enum removed")
+		return
+	case len(base) == 0:
+		d.add(Added, d.breaks(dir, true), location, "enum added")
+		return
+	}
+	for _, v := range base {
+		if !containsValue(revision, v) {
+			d.add(Removed, d.breaks(dir, true), location, "enum value %v removed", v)
+		}
+	}
+	for _, v := range revision {
+		if !containsValue(base, v) {
+			d.add(Added, d.breaks(dir, false), location, "enum value %v added", v)
+		}
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) diffMin(dir direction, location string, base, revision *float64) {
+	if equalFloat64Ptr(base, revision) {
+		return
+	}
+	narrowed := revision != nil && (base == nil || *revision > *base)
+	d.add(Changed, d.breaks(dir, narrowed), location, "changed from %s to %s", formatFloat64Ptr(base), formatFloat64Ptr(revision))
+}
+
+func (d *differ) diffMax(dir direction, location string, base, revision *float64) {
+	if equalFloat64Ptr(base, revision) {
+		return
+	}
+	narrowed := revision != nil && (base == nil || *revision < *base)
+	d.add(Changed, d.breaks(dir, narrowed), location, "changed from %s to %s", formatFloat64Ptr(base), formatFloat64Ptr(revision))
+}
+
+func (d *differ) diffProperties(dir direction, location string, base, revision *openapi3.Schema) {
+	names := make([]string, 0, len(base.Properties)+len(revision.Properties))
+	for name := range base.Properties {
+		names = append(names, name)
+	}
+	for name := range revision.Properties {
+		if _, ok := base.Properties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propLocation := join(location, "properties."+name)
+		b, r := base.Properties[name], revision.Properties[name]
+		bRequired, rRequired := isRequired(base, name), isRequired(revision, name)
+		switch {
+		case r == nil:
+			if dir == request {
+				d.add(Removed, !allowsAdditionalProperties(revision), propLocation, "property removed")
+			} else {
+				d.add(Removed, true, propLocation, "property removed")
+			}
+		case b == nil:
+			if dir == request {
+				d.add(Added, rRequired, propLocation, "property added")
+			} else {
+				d.add(Added, false, propLocation, "property added")
+			}
+		default:
+			if !bRequired && rRequired {
+				d.add(Changed, dir == request, propLocation, "property became required")
+			} else if bRequired && !rRequired {
+				d.add(Changed, dir == response, propLocation, "property became optional")
+			}
+			d.diffSchemaRefs(dir, propLocation, b, r)
+		}
+	}
+}
+
+// diffSchemaList compares the members of allOf (all being true), anyOf or oneOf lists.
+// Members are paired by $ref then by structure, whatever their order,
+// and those left are paired in order.
+func (d *differ) diffSchemaList(dir direction, location string, base, revision openapi3.SchemaRefs, all bool) {
+	pairs := make([]int, len(base)) // Index in revision of the member paired with each of base, -1 for none
+	paired := make([]bool, len(revision))
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	pair := func(matches func(b, r *openapi3.SchemaRef) bool) {
+		for i, b := range base {
+			if pairs[i] >= 0 {
+				continue
+			}
+			for j, r := range revision {
+				if !paired[j] && matches(b, r) {
+					pairs[i], paired[j] = j, true
+					break
+				}
+			}
+		}
+	}
+	pair(func(b, r *openapi3.SchemaRef) bool { return b.Ref != "" && b.Ref == r.Ref })
+	pair(func(b, r *openapi3.SchemaRef) bool { return !d.differs(b, r) })
+	pair(func(b, r *openapi3.SchemaRef) bool { return b.Ref == "" && r.Ref == "" })
+
+	for i, j := range pairs {
+		memberLocation := fmt.Sprintf("%s.%d", location, i)
+		if j < 0 {
+			// Removing a member of allOf widens the schema, that of anyOf or oneOf narrows it
+			d.add(Removed, d.breaks(dir, !all), memberLocation, "schema removed")
+			continue
+		}
+		d.diffSchemaRefs(dir, memberLocation, base[i], revision[j])
+	}
+	for j := range revision {
+		if !paired[j] {
+			d.add(Added, d.breaks(dir, all), fmt.Sprintf("%s.%d", location, j), "schema added")
+		}
+	}
+}
+
+func typeOf(schema *openapi3.Schema) string {
+	if len(schema.Types) != 0 {
+		types := append([]string(nil), schema.Types...)
+		sort.Strings(types)
+		return fmt.Sprint(types)
+	}
+	return schema.Type
+}
+
+func isRequired(schema *openapi3.Schema, name string) bool {
+	for _, k := range schema.Required {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
+
+func allowsAdditionalProperties(schema *openapi3.Schema) bool {
+	v := schema.AdditionalPropertiesAllowed
+	return v == nil || *v
+}
+
+func uint64Ptr(v uint64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	f := float64(v)
+	return &f
+}
+
+func uint64PtrToFloat(v *uint64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func equalFloat64Ptr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func formatFloat64Ptr(v *float64) string {
+	if v == nil {
+		return "none"
+	}
+	return fmt.Sprint(*v)
+}
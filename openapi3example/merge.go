@@ -0,0 +1,132 @@
+package openapi3example
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// flatten returns schema with its allOf subschemas merged in.
+// The returned schema may share data with schema and must not be modified
+// other than through its top-level fields.
+func flatten(schema *openapi3.Schema) *openapi3.Schema {
+	if len(schema.AllOf) == 0 {
+		return schema
+	}
+	merged := *schema
+	merged.AllOf = nil
+	merged.Properties = copySchemas(schema.Properties)
+	merged.Required = append([]string(nil), schema.Required...)
+	for _, ref := range schema.AllOf {
+		if ref.Value != nil {
+			mergeInto(&merged, flatten(ref.Value))
+		}
+	}
+	return &merged
+}
+
+func copySchemas(schemas openapi3.Schemas) openapi3.Schemas {
+	if schemas == nil {
+		return nil
+	}
+	c := make(openapi3.Schemas, len(schemas))
+	for k, v := range schemas {
+		c[k] = v
+	}
+	return c
+}
+
+// mergeInto adds the constraints of src to dst, whose Properties and Required
+// must not be shared with another schema.
+func mergeInto(dst, src *openapi3.Schema) {
+	if dst.Type == "" && len(dst.Types) == 0 {
+		dst.Type, dst.Types = src.Type, src.Types
+	}
+	if dst.Format == "" {
+		dst.Format = src.Format
+	}
+	if dst.Pattern == "" {
+		dst.Pattern = src.Pattern
+	}
+	if len(dst.Enum) == 0 {
+		dst.Enum = src.Enum
+	}
+	if dst.Const == nil {
+		dst.Const = src.Const
+	}
+	if dst.Nullable {
+		dst.Nullable = src.Nullable
+	}
+
+	// Numbers
+	if src.Min != nil && (dst.Min == nil || *src.Min > *dst.Min) {
+		dst.Min, dst.ExclusiveMin = src.Min, src.ExclusiveMin
+	}
+	if src.Max != nil && (dst.Max == nil || *src.Max < *dst.Max) {
+		dst.Max, dst.ExclusiveMax = src.Max, src.ExclusiveMax
+	}
+	if src.ExclusiveMinValue != nil && (dst.ExclusiveMinValue == nil || *src.ExclusiveMinValue > *dst.ExclusiveMinValue) {
+		dst.ExclusiveMinValue = src.ExclusiveMinValue
+	}
+	if src.ExclusiveMaxValue != nil && (dst.ExclusiveMaxValue == nil || *src.ExclusiveMaxValue < *dst.ExclusiveMaxValue) {
+		dst.ExclusiveMaxValue = src.ExclusiveMaxValue
+	}
+	if dst.MultipleOf == nil {
+		dst.MultipleOf = src.MultipleOf
+	}
+
+	// Strings
+	if src.MinLength > dst.MinLength {
+		dst.MinLength = src.MinLength
+	}
+	if src.MaxLength != nil && (dst.MaxLength == nil || *src.MaxLength < *dst.MaxLength) {
+		dst.MaxLength = src.MaxLength
+	}
+
+	// Arrays
+	if src.MinItems > dst.MinItems {
+		dst.MinItems = src.MinItems
+	}
+	if src.MaxItems != nil && (dst.MaxItems == nil || *src.MaxItems < *dst.MaxItems) {
+		dst.MaxItems = src.MaxItems
+	}
+	if dst.Items == nil {
+		dst.Items = src.Items
+	}
+	if len(dst.PrefixItems) == 0 {
+		dst.PrefixItems = src.PrefixItems
+	}
+	dst.UniqueItems = dst.UniqueItems || src.UniqueItems
+
+	// Objects
+	for name, ref := range src.Properties {
+		if _, ok := dst.Properties[name]; !ok {
+			if dst.Properties == nil {
+				dst.Properties = make(openapi3.Schemas)
+			}
+			dst.Properties[name] = ref
+		}
+	}
+	dst.Required = append(dst.Required, src.Required...)
+	if src.MinProps > dst.MinProps {
+		dst.MinProps = src.MinProps
+	}
+	if src.MaxProps != nil && (dst.MaxProps == nil || *src.MaxProps < *dst.MaxProps) {
+		dst.MaxProps = src.MaxProps
+	}
+	if dst.AdditionalProperties == nil {
+		dst.AdditionalProperties = src.AdditionalProperties
+	}
+	if v := src.AdditionalPropertiesAllowed; v != nil && !*v {
+		dst.AdditionalPropertiesAllowed = v
+	}
+	if len(dst.DependentSchemas) == 0 {
+		dst.DependentSchemas = src.DependentSchemas
+	}
+
+	// Subschemas left to pick from
+	if len(dst.OneOf) == 0 && len(dst.AnyOf) == 0 {
+		dst.OneOf, dst.AnyOf = src.OneOf, src.AnyOf
+		if dst.Discriminator == nil {
+			dst.Discriminator = src.Discriminator
+		}
+	}
+}
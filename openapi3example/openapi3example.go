@@ -0,0 +1,486 @@
+// Package openapi3example generates example values from OpenAPIv3 schemas.
+//
+// Generated values are plain JSON values (nil, bool, float64, string,
+// []interface{} and map[string]interface{}) that pass Schema.VisitJSON.
+// Generation is deterministic for a given seed.
+package openapi3example
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Option allows tweaking value generation
+type Option func(*generatorOpt)
+
+type generatorOpt struct {
+	seed        int64
+	maxDepth    int
+	maxAttempts int
+	useExamples bool
+}
+
+// WithSeed sets the seed of the generator's source of randomness.
+// Generators with the same seed and options generate the same values.
+func WithSeed(seed int64) Option {
+	return func(x *generatorOpt) { x.seed = seed }
+}
+
+// WithMaxDepth limits how deep the generator goes into nested schemas,
+// which matters for recursive schemas. Optional properties and array items
+// are no longer generated past that depth.
+func WithMaxDepth(depth int) Option {
+	return func(x *generatorOpt) { x.maxDepth = depth }
+}
+
+// WithMaxAttempts sets how many values are generated before giving up
+// on finding one that validates against the schema.
+func WithMaxAttempts(attempts int) Option {
+	return func(x *generatorOpt) { x.maxAttempts = attempts }
+}
+
+// UseExamples makes the generator prefer a schema's example
+// or default value, when valid, to a generated one.
+func UseExamples() Option {
+	return func(x *generatorOpt) { x.useExamples = true }
+}
+
+// Generator generates values matching schemas.
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	opts generatorOpt
+	rand *rand.Rand
+}
+
+// NewGenerator creates a Generator, seeded with 0 unless WithSeed is given.
+func NewGenerator(opts ...Option) *Generator {
+	gOpt := &generatorOpt{
+		maxDepth:    6,
+		maxAttempts: 10,
+	}
+	for _, f := range opts {
+		f(gOpt)
+	}
+	return &Generator{
+		opts: *gOpt,
+		rand: rand.New(rand.NewSource(gOpt.seed)),
+	}
+}
+
+// Generate returns a value matching schema, generated with a new Generator.
+func Generate(schema *openapi3.Schema, opts ...Option) (interface{}, error) {
+	return NewGenerator(opts...).Generate(schema)
+}
+
+// Generate returns a value matching schema.
+// An error is returned when no generated value validates against the schema,
+// e.g. when the schema is not satisfiable.
+func (g *Generator) Generate(schema *openapi3.Schema) (interface{}, error) {
+	var err error
+	for attempt := 0; attempt < g.opts.maxAttempts; attempt++ {
+		value := g.generate(schema, 0)
+		if err = schema.VisitJSON(value); err == nil {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to generate a value matching the schema: %v", err)
+}
+
+func (g *Generator) generate(schema *openapi3.Schema, depth int) interface{} {
+	if g.opts.useExamples {
+		for _, v := range []interface{}{schema.Example, schema.Default} {
+			if v != nil && schema.VisitJSON(v) == nil {
+				return v
+			}
+		}
+	}
+
+	if depth > 2*g.opts.maxDepth {
+		// Only required properties of recursive schemas get this deep
+		return nil
+	}
+
+	schema = flatten(schema)
+	if branches := schema.OneOf; len(branches) != 0 {
+		return g.generateBranch(schema, branches, depth)
+	}
+	if branches := schema.AnyOf; len(branches) != 0 {
+		return g.generateBranch(schema, branches, depth)
+	}
+
+	if schema.Const != nil {
+		return schema.Const
+	}
+	if enum := schema.Enum; len(enum) != 0 {
+		return enum[g.rand.Intn(len(enum))]
+	}
+
+	switch g.pickType(schema) {
+	case "null":
+		return nil
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	case "integer":
+		return g.generateNumber(schema, true)
+	case "number":
+		return g.generateNumber(schema, false)
+	case "string":
+		return g.generateString(schema)
+	case "array":
+		return g.generateArray(schema, depth)
+	case "object":
+		return g.generateObject(schema, depth)
+	}
+	return nil
+}
+
+// generateBranch generates a value for one of the oneOf or anyOf branches,
+// setting the discriminator property when there is one.
+func (g *Generator) generateBranch(schema *openapi3.Schema, branches openapi3.SchemaRefs, depth int) interface{} {
+	i := g.rand.Intn(len(branches))
+	branch := branches[i]
+	if branch.Value == nil {
+		return nil
+	}
+	merged := *schema
+	merged.OneOf, merged.AnyOf = nil, nil
+	merged.Properties = copySchemas(schema.Properties)
+	merged.Required = append([]string(nil), schema.Required...)
+	mergeInto(&merged, flatten(branch.Value))
+	value := g.generate(&merged, depth)
+
+	if d := schema.Discriminator; d != nil {
+		if obj, ok := value.(map[string]interface{}); ok {
+			if name := discriminatorValue(d, branch.Ref); name != "" {
+				obj[d.PropertyName] = name
+			}
+		}
+	}
+	return value
+}
+
+// discriminatorValue returns the discriminator value selecting ref.
+func discriminatorValue(d *openapi3.Discriminator, ref string) string {
+	names := make([]string, 0, len(d.Mapping))
+	for name := range d.Mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if d.Mapping[name] == ref {
+			return name
+		}
+	}
+	if i := strings.LastIndexByte(ref, '/'); i >= 0 {
+		return ref[i+1:]
+	}
+	return ""
+}
+
+func (g *Generator) pickType(schema *openapi3.Schema) string {
+	types := schema.Types
+	if len(types) == 0 && schema.Type != "" {
+		types = []string{schema.Type}
+	}
+	if len(types) == 0 {
+		switch {
+		case len(schema.Properties) != 0 || len(schema.Required) != 0 || schema.AdditionalProperties != nil:
+			return "object"
+		case schema.Items != nil || len(schema.PrefixItems) != 0:
+			return "array"
+		case schema.Pattern != "" || schema.Format != "" || schema.MinLength != 0 || schema.MaxLength != nil:
+			return "string"
+		case schema.Min != nil || schema.Max != nil || schema.MultipleOf != nil:
+			return "number"
+		}
+		return "string"
+	}
+	// Favor non-null types so that values are informative.
+	nonNull := make([]string, 0, len(types))
+	for _, typ := range types {
+		if typ != "null" {
+			nonNull = append(nonNull, typ)
+		}
+	}
+	if len(nonNull) == 0 {
+		return "null"
+	}
+	return nonNull[g.rand.Intn(len(nonNull))]
+}
+
+func (g *Generator) generateNumber(schema *openapi3.Schema, integer bool) float64 {
+	lo, loExclusive := math.Inf(-1), false
+	if v := schema.Min; v != nil {
+		lo, loExclusive = *v, schema.ExclusiveMin
+	}
+	if v := schema.ExclusiveMinValue; v != nil && *v >= lo {
+		lo, loExclusive = *v, true
+	}
+	hi, hiExclusive := math.Inf(1), false
+	if v := schema.Max; v != nil {
+		hi, hiExclusive = *v, schema.ExclusiveMax
+	}
+	if v := schema.ExclusiveMaxValue; v != nil && *v <= hi {
+		hi, hiExclusive = *v, true
+	}
+	switch {
+	case math.IsInf(lo, -1) && math.IsInf(hi, 1):
+		lo, hi = 0, 100
+	case math.IsInf(lo, -1):
+		lo = hi - 100
+	case math.IsInf(hi, 1):
+		hi = lo + 100
+	}
+
+	step := 0.0
+	if v := schema.MultipleOf; v != nil && *v > 0 {
+		step = *v
+	}
+	if integer && (step == 0 || step != math.Trunc(step)) {
+		if step == 0 {
+			step = 1
+		} else {
+			// Multiples of both the step and 1
+			step = lcm(step)
+		}
+	}
+	if step == 0 {
+		if lo == hi {
+			return lo
+		}
+		v := lo + (hi-lo)*(0.1+0.8*g.rand.Float64())
+		if rounded := math.Round(v*100) / 100; rounded > lo && rounded < hi {
+			v = rounded
+		}
+		return v
+	}
+
+	first := math.Ceil(lo/step) * step
+	if loExclusive && first == lo {
+		first += step
+	}
+	last := math.Floor(hi/step) * step
+	if hiExclusive && last == hi {
+		last -= step
+	}
+	if last < first {
+		return first
+	}
+	n := int64((last-first)/step) + 1
+	if n > 1000 {
+		n = 1000
+	}
+	return first + float64(g.rand.Int63n(n))*step
+}
+
+// lcm returns the least multiple of step that is an integer, or step if there is none small enough.
+func lcm(step float64) float64 {
+	for k := 1.0; k <= 1000; k++ {
+		if v := step * k; math.Abs(v-math.Round(v)) < 1e-9 {
+			return math.Round(v)
+		}
+	}
+	return step
+}
+
+func (g *Generator) generateString(schema *openapi3.Schema) string {
+	var s string
+	switch schema.Format {
+	case "date":
+		s = g.time().Format("2006-01-02")
+	case "date-time":
+		s = g.time().Format(time.RFC3339)
+	case "time":
+		s = g.time().Format("15:04:05")
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		s = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		s = g.word(3, 10) + "@example.com"
+	case "hostname":
+		s = g.word(3, 10) + ".example.com"
+	case "ipv4":
+		s = fmt.Sprintf("%d.%d.%d.%d", 1+g.rand.Intn(223), g.rand.Intn(256), g.rand.Intn(256), 1+g.rand.Intn(254))
+	case "ipv6":
+		s = fmt.Sprintf("2001:db8::%x:%x", g.rand.Intn(0x10000), g.rand.Intn(0x10000))
+	case "uri", "url":
+		s = "https://example.com/" + g.word(3, 10)
+	case "byte":
+		s = base64.StdEncoding.EncodeToString([]byte(g.word(3, 10)))
+	default:
+		if schema.Pattern != "" {
+			if v, err := g.generatePattern(schema.Pattern); err == nil {
+				return v
+			}
+		}
+		min := int(schema.MinLength)
+		max := min + 10
+		if v := schema.MaxLength; v != nil && int(*v) < max {
+			max = int(*v)
+		}
+		if min == 0 && max > 0 {
+			min = 1
+		}
+		return g.word(min, max)
+	}
+	return s
+}
+
+func (g *Generator) time() time.Time {
+	return time.Date(2000+g.rand.Intn(30), time.Month(1+g.rand.Intn(12)), 1+g.rand.Intn(28),
+		g.rand.Intn(24), g.rand.Intn(60), g.rand.Intn(60), 0, time.UTC)
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+// word returns a random lowercase word with a length in [min, max].
+func (g *Generator) word(min, max int) string {
+	n := min
+	if max > min {
+		n += g.rand.Intn(max - min + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[g.rand.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+func (g *Generator) generateArray(schema *openapi3.Schema, depth int) []interface{} {
+	min := int(schema.MinItems)
+	if min < len(schema.PrefixItems) && depth < g.opts.maxDepth {
+		min = len(schema.PrefixItems)
+	}
+	max := min + 3
+	if v := schema.MaxItems; v != nil && int(*v) < max {
+		max = int(*v)
+	}
+	if depth >= g.opts.maxDepth {
+		max = min
+	}
+	n := min
+	if max > min {
+		n += g.rand.Intn(max - min + 1)
+	}
+
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		ref := schema.Items
+		if i < len(schema.PrefixItems) {
+			ref = schema.PrefixItems[i]
+		}
+		var itemSchema *openapi3.Schema
+		if ref != nil {
+			itemSchema = ref.Value
+		}
+		if itemSchema == nil {
+			items = append(items, g.word(1, 10))
+			continue
+		}
+		var item interface{}
+		for attempt := 0; attempt < g.opts.maxAttempts; attempt++ {
+			item = g.generate(itemSchema, depth+1)
+			if !schema.UniqueItems || !contains(items, item) {
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func contains(items []interface{}, item interface{}) bool {
+	for _, v := range items {
+		if fmt.Sprint(v) == fmt.Sprint(item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Generator) generateObject(schema *openapi3.Schema, depth int) map[string]interface{} {
+	obj := make(map[string]interface{})
+	max := -1
+	if v := schema.MaxProps; v != nil {
+		max = int(*v)
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+		obj[name] = g.generateProperty(schema, name, depth)
+	}
+
+	optional := make([]string, 0, len(schema.Properties))
+	for _, name := range sortedNames(schema.Properties) {
+		if !required[name] {
+			optional = append(optional, name)
+		}
+	}
+	for i, name := range optional {
+		if max >= 0 && len(obj) >= max {
+			break
+		}
+		mustAdd := len(obj)+len(optional)-i <= int(schema.MinProps)
+		if mustAdd || (depth < g.opts.maxDepth && g.rand.Intn(2) == 0) {
+			obj[name] = g.generateProperty(schema, name, depth)
+		}
+	}
+
+	allowed := schema.AdditionalPropertiesAllowed == nil || *schema.AdditionalPropertiesAllowed
+	for i := 1; len(obj) < int(schema.MinProps) && allowed; i++ {
+		name := fmt.Sprintf("property%d", i)
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		if ref := schema.AdditionalProperties; ref != nil && ref.Value != nil {
+			obj[name] = g.generate(ref.Value, depth+1)
+		} else {
+			obj[name] = g.word(1, 10)
+		}
+	}
+
+	for _, name := range sortedNames(schema.DependentSchemas) {
+		ref := schema.DependentSchemas[name]
+		if _, ok := obj[name]; !ok || ref.Value == nil {
+			continue
+		}
+		if dep, ok := g.generate(ref.Value, depth).(map[string]interface{}); ok {
+			for k, v := range dep {
+				if _, ok := obj[k]; !ok {
+					obj[k] = v
+				}
+			}
+		}
+	}
+	return obj
+}
+
+func (g *Generator) generateProperty(schema *openapi3.Schema, name string, depth int) interface{} {
+	if ref := schema.Properties[name]; ref != nil && ref.Value != nil {
+		return g.generate(ref.Value, depth+1)
+	}
+	if ref := schema.AdditionalProperties; ref != nil && ref.Value != nil {
+		return g.generate(ref.Value, depth+1)
+	}
+	return g.word(1, 10)
+}
+
+func sortedNames(schemas openapi3.Schemas) []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
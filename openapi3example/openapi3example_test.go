@@ -0,0 +1,175 @@
+package openapi3example
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.0
+info:
+  title: Examples
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Formats:
+      type: object
+      required: [date, dateTime, email, ipv4, ipv6, byte]
+      properties:
+        date: {type: string, format: date}
+        dateTime: {type: string, format: date-time}
+        email: {type: string, format: email}
+        ipv4: {type: string, format: ipv4}
+        ipv6: {type: string, format: ipv6}
+        byte: {type: string, format: byte}
+    Numbers:
+      type: object
+      required: [small, exclusive, multiple, negative, float]
+      properties:
+        small: {type: integer, minimum: 3, maximum: 5}
+        exclusive: {type: integer, minimum: 3, maximum: 5, exclusiveMinimum: true, exclusiveMaximum: true}
+        multiple: {type: integer, minimum: 10, maximum: 100, multipleOf: 7}
+        negative: {type: number, maximum: -1000}
+        float: {type: number, minimum: 0.1, maximum: 0.2, exclusiveMaximum: true}
+    Strings:
+      type: object
+      required: [short, long, pattern, enum]
+      properties:
+        short: {type: string, maxLength: 2}
+        long: {type: string, minLength: 30}
+        pattern: {type: string, pattern: '^[A-Z]{3}-\d{2,4}(x|y)?$'}
+        enum: {type: string, enum: [a, b, c]}
+    Arrays:
+      type: object
+      required: [unique, bounded]
+      properties:
+        unique:
+          type: array
+          uniqueItems: true
+          minItems: 3
+          items: {type: integer, minimum: 0, maximum: 5}
+        bounded:
+          type: array
+          minItems: 2
+          maxItems: 2
+          items: {type: boolean}
+    Tree:
+      type: object
+      required: [value]
+      properties:
+        value: {type: integer}
+        children:
+          type: array
+          items: {$ref: '#/components/schemas/Tree'}
+    Base:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string, format: byte}
+    Composed:
+      allOf:
+      - $ref: '#/components/schemas/Base'
+      - type: object
+        required: [name]
+        properties:
+          name: {type: string, minLength: 1}
+    Cat:
+      type: object
+      required: [kind, lives]
+      properties:
+        kind: {type: string}
+        lives: {type: integer, minimum: 1, maximum: 9}
+    Dog:
+      type: object
+      required: [kind, barks]
+      properties:
+        kind: {type: string}
+        barks: {type: boolean}
+    Pet:
+      oneOf:
+      - $ref: '#/components/schemas/Cat'
+      - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: '#/components/schemas/Cat'
+          dog: '#/components/schemas/Dog'
+    AnyOf:
+      anyOf:
+      - {type: string, minLength: 5}
+      - {type: integer, minimum: 100}
+    Bounded:
+      type: object
+      minProperties: 3
+      maxProperties: 3
+      properties:
+        a: {type: string}
+      additionalProperties: {type: integer}
+`)
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+
+	for name, ref := range doc.Components.Schemas {
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				value, err := Generate(ref.Value, WithSeed(seed))
+				require.NoError(t, err)
+				err = ref.Value.VisitJSON(value)
+				require.NoError(t, err, "seed %d generated %#v", seed, value)
+			}
+		})
+	}
+
+	t.Run("deterministic", func(t *testing.T) {
+		schema := doc.Components.Schemas["Tree"].Value
+		v1, err := Generate(schema, WithSeed(42))
+		require.NoError(t, err)
+		v2, err := Generate(schema, WithSeed(42))
+		require.NoError(t, err)
+		require.Equal(t, v1, v2)
+	})
+
+	t.Run("discriminator", func(t *testing.T) {
+		g := NewGenerator(WithSeed(1))
+		kinds := make(map[interface{}]bool)
+		for i := 0; i < 20; i++ {
+			value, err := g.Generate(doc.Components.Schemas["Pet"].Value)
+			require.NoError(t, err)
+			kinds[value.(map[string]interface{})["kind"]] = true
+		}
+		require.Equal(t, map[interface{}]bool{"cat": true, "dog": true}, kinds)
+	})
+}
+
+func TestGenerateUUID(t *testing.T) {
+	schema := openapi3.NewStringSchema().WithFormat("uuid")
+	value, err := Generate(schema)
+	require.NoError(t, err)
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, value)
+}
+
+func TestGenerateUseExamples(t *testing.T) {
+	schema := openapi3.NewStringSchema().WithMaxLength(5)
+	schema.Example = "hello"
+	value, err := Generate(schema, UseExamples())
+	require.NoError(t, err)
+	require.Equal(t, "hello", value)
+
+	schema.Example = "too long"
+	value, err = Generate(schema, UseExamples())
+	require.NoError(t, err)
+	require.NotEqual(t, "too long", value)
+}
+
+func TestGenerateUnsatisfiable(t *testing.T) {
+	schema := openapi3.NewIntegerSchema().WithMin(5).WithMax(3)
+	_, err := Generate(schema)
+	require.Error(t, err)
+}
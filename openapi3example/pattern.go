@@ -0,0 +1,91 @@
+package openapi3example
+
+import (
+	"regexp/syntax"
+	"strings"
+)
+
+// maxRepeat bounds unbounded repetitions (*, + and {n,}) in patterns.
+const maxRepeat = 3
+
+// generatePattern returns a string matching the regular expression pattern.
+func (g *Generator) generatePattern(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	g.generateRegexp(re, &sb)
+	return sb.String(), nil
+}
+
+func (g *Generator) generateRegexp(re *syntax.Regexp, sb *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(g.runeInClass(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(letters[g.rand.Intn(len(letters))])
+	case syntax.OpCapture:
+		g.generateRegexp(re.Sub[0], sb)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.generateRegexp(sub, sb)
+		}
+	case syntax.OpAlternate:
+		g.generateRegexp(re.Sub[g.rand.Intn(len(re.Sub))], sb)
+	case syntax.OpStar:
+		g.repeat(re.Sub[0], 0, maxRepeat, sb)
+	case syntax.OpPlus:
+		g.repeat(re.Sub[0], 1, 1+maxRepeat, sb)
+	case syntax.OpQuest:
+		g.repeat(re.Sub[0], 0, 1, sb)
+	case syntax.OpRepeat:
+		max := re.Max
+		if max < 0 {
+			max = re.Min + maxRepeat
+		}
+		g.repeat(re.Sub[0], re.Min, max, sb)
+	}
+	// Anchors, word boundaries and empty matches produce no text.
+}
+
+func (g *Generator) repeat(re *syntax.Regexp, min, max int, sb *strings.Builder) {
+	n := min
+	if max > min {
+		n += g.rand.Intn(max - min + 1)
+	}
+	for i := 0; i < n; i++ {
+		g.generateRegexp(re, sb)
+	}
+}
+
+// runeInClass picks a rune of a character class given as ranges,
+// favoring printable ASCII characters.
+func (g *Generator) runeInClass(ranges []rune) rune {
+	type span struct{ lo, hi rune }
+	printable := make([]span, 0, len(ranges)/2)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+		if hi > '~' {
+			hi = '~'
+		}
+		if lo <= hi {
+			printable = append(printable, span{lo, hi})
+		}
+	}
+	if len(printable) == 0 {
+		if len(ranges) == 0 {
+			return 'a'
+		}
+		return ranges[0]
+	}
+	s := printable[g.rand.Intn(len(printable))]
+	return s.lo + rune(g.rand.Intn(int(s.hi-s.lo)+1))
+}
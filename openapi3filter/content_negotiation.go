@@ -0,0 +1,170 @@
+package openapi3filter
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrNotAcceptable is returned with Options.NegotiateContent when the Accept header
+// of a request matches none of the media types of its operation's responses.
+var ErrNotAcceptable = errors.New("no acceptable media type")
+
+// mediaRange is a media range of an Accept header.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// specificity ranks */* below type/* below type/subtype.
+func (r mediaRange) specificity() int {
+	switch {
+	case r.typ == "*":
+		return 0
+	case r.subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+// parseAccept returns the media ranges of an Accept header value, skipping invalid ones.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		i := strings.IndexByte(mediaType, '/')
+		if i < 0 {
+			continue
+		}
+		r := mediaRange{typ: mediaType[:i], subtype: mediaType[i+1:], q: 1}
+		if r.typ == "*" && r.subtype != "*" {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if r.q, err = strconv.ParseFloat(q, 64); err != nil || r.q < 0 || r.q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+func matchesPart(a, b string) bool {
+	return a == "*" || b == "*" || a == b
+}
+
+// NegotiateMediaType returns the media type among offered that best matches
+// the Accept header value accept, following the order of offered on ties.
+// Offered media types may be wildcards (e.g. "application/*"):
+// the negotiated type is then the matching media range, if more specific.
+// An empty Accept header accepts anything.
+func NegotiateMediaType(accept string, offered []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+	ranges := parseAccept(accept)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offered {
+		mediaType, _, err := mime.ParseMediaType(offer)
+		if err != nil {
+			continue
+		}
+		i := strings.IndexByte(mediaType, '/')
+		if i < 0 {
+			continue
+		}
+		typ, subtype := mediaType[:i], mediaType[i+1:]
+
+		// The most specific matching range sets the quality of the offer
+		var match *mediaRange
+		for j := range ranges {
+			r := &ranges[j]
+			if !matchesPart(r.typ, typ) || !matchesPart(r.subtype, subtype) {
+				continue
+			}
+			if match == nil || r.specificity() > match.specificity() {
+				match = r
+			}
+		}
+		if match == nil || match.q <= bestQ {
+			continue
+		}
+		bestQ = match.q
+		best = mediaType
+		if typ == "*" || subtype == "*" {
+			if match.typ != "*" {
+				typ = match.typ
+			}
+			if match.subtype != "*" {
+				subtype = match.subtype
+			}
+			best = typ + "/" + subtype
+		}
+	}
+	return best, bestQ > 0
+}
+
+// responseMediaTypes returns the media types of the responses of operation,
+// ordered by status code then media type.
+func responseMediaTypes(operation *openapi3.Operation) []string {
+	statuses := make([]string, 0, len(operation.Responses))
+	for status := range operation.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var mediaTypes []string
+	seen := make(map[string]struct{})
+	for _, status := range statuses {
+		ref := operation.Responses[status]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		contentTypes := make([]string, 0, len(ref.Value.Content))
+		for contentType := range ref.Value.Content {
+			contentTypes = append(contentTypes, contentType)
+		}
+		sort.Strings(contentTypes)
+		for _, contentType := range contentTypes {
+			if _, ok := seen[contentType]; !ok {
+				seen[contentType] = struct{}{}
+				mediaTypes = append(mediaTypes, contentType)
+			}
+		}
+	}
+	return mediaTypes
+}
+
+// validateAccept negotiates the media type of the response to input's request,
+// recording it in its DecodedRequest.
+func validateAccept(input *RequestValidationInput) error {
+	offered := responseMediaTypes(input.Route.Operation)
+	if len(offered) == 0 {
+		// Responses have no content to negotiate
+		return nil
+	}
+	accept := strings.Join(input.Request.Header.Values("Accept"), ",")
+	mediaType, ok := NegotiateMediaType(accept, offered)
+	if !ok {
+		return &RequestError{
+			Input:  input,
+			Reason: fmt.Sprintf("header Accept %q matches none of %s", accept, strings.Join(offered, ", ")),
+			Err:    ErrNotAcceptable,
+		}
+	}
+	decodedRequest(input).MediaType = mediaType
+	return nil
+}
@@ -0,0 +1,118 @@
+package openapi3filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	offered := []string{"application/json", "application/xml", "text/*"}
+	for _, test := range []struct {
+		accept   string
+		expected string
+		ok       bool
+	}{
+		{"", "application/json", true},
+		{"*/*", "application/json", true},
+		{"application/xml", "application/xml", true},
+		{"application/xml;q=0.5, application/json;q=0.4", "application/xml", true},
+		{"application/*;q=0.5, application/json;q=0", "application/xml", true},
+		{"text/csv", "text/csv", true},
+		{"text/*", "text/*", true},
+		{"image/png, */*;q=0.1", "application/json", true},
+		{"image/png", "", false},
+		{"application/json;q=0", "", false},
+		{"invalid, application/xml;q=2", "", false},
+	} {
+		t.Run(test.accept, func(t *testing.T) {
+			mediaType, ok := NegotiateMediaType(test.accept, offered)
+			require.Equal(t, test.ok, ok)
+			require.Equal(t, test.expected, mediaType)
+		})
+	}
+}
+
+func TestValidateRequestNegotiateContent(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+        default:
+          description: Error
+          content:
+            application/problem+json:
+              schema:
+                type: object
+    delete:
+      responses:
+        '204':
+          description: Deleted
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func(method, accept string, options *Options) (*RequestValidationInput, error) {
+		req := httptest.NewRequest(method, "/pets", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		input := &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		return input, ValidateRequest(context.Background(), input)
+	}
+
+	_, err = validate(http.MethodGet, "application/xml", &Options{})
+	require.NoError(t, err)
+
+	options := &Options{NegotiateContent: true}
+	input, err := validate(http.MethodGet, "application/xml", options)
+	require.EqualError(t, err, `header Accept "application/xml" matches none of application/json, application/problem+json: no acceptable media type`)
+	rec := httptest.NewRecorder()
+	enc := &ValidationErrorEncoder{Encoder: (ErrorEncoder)(DefaultErrorEncoder)}
+	enc.Encode(context.Background(), err, rec)
+	require.Equal(t, http.StatusNotAcceptable, rec.Code)
+	require.Nil(t, input.DecodedRequest())
+
+	input, err = validate(http.MethodGet, "application/problem+json, application/*;q=0.8", options)
+	require.NoError(t, err)
+	require.Equal(t, "application/problem+json", input.DecodedRequest().MediaType)
+
+	input, err = validate(http.MethodGet, "", options)
+	require.NoError(t, err)
+	require.Equal(t, "application/json", input.DecodedRequest().MediaType)
+
+	// Nothing to negotiate without content
+	_, err = validate(http.MethodDelete, "application/xml", options)
+	require.NoError(t, err)
+}
@@ -0,0 +1,88 @@
+package openapi3filter
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type decodedRequestKey struct{}
+
+// DecodedRequest holds the values of a request's parameters and body
+// as decoded during validation, with schema defaults applied when
+// Options.FillDefaults is set.
+//
+// Values are typed after their schema: numbers are float64, arrays are
+// []interface{} and objects are map[string]interface{}.
+// Parameters absent from the request are absent from these maps.
+type DecodedRequest struct {
+	PathParams   map[string]interface{}
+	QueryParams  map[string]interface{}
+	HeaderParams map[string]interface{}
+	CookieParams map[string]interface{}
+
+	// Body is the value returned by the BodyDecoder, nil if the body was not decoded.
+	Body interface{}
+
+	// MediaType is the media type negotiated for the response from the Accept header,
+	// with Options.NegotiateContent.
+	MediaType string
+}
+
+// DecodedRequestFromContext returns the DecodedRequest stored in ctx by ValidationHandler,
+// or nil if there is none.
+func DecodedRequestFromContext(ctx context.Context) *DecodedRequest {
+	if v, ok := ctx.Value(decodedRequestKey{}).(*DecodedRequest); ok {
+		return v
+	}
+	return nil
+}
+
+// withDecodedRequest returns a shallow copy of req whose context carries decoded.
+func withDecodedRequest(req *http.Request, decoded *DecodedRequest) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), decodedRequestKey{}, decoded))
+}
+
+// decodedRequest returns the DecodedRequest of input, creating it when there is none.
+func decodedRequest(input *RequestValidationInput) *DecodedRequest {
+	if input.decoded == nil {
+		input.decoded = &DecodedRequest{}
+	}
+	return input.decoded
+}
+
+// Param returns the decoded value of the parameter with the given location and name.
+func (decoded *DecodedRequest) Param(in, name string) (value interface{}, ok bool) {
+	params := decoded.params(in)
+	if params == nil {
+		return nil, false
+	}
+	value, ok = (*params)[name]
+	return
+}
+
+func (decoded *DecodedRequest) params(in string) *map[string]interface{} {
+	switch in {
+	case openapi3.ParameterInPath:
+		return &decoded.PathParams
+	case openapi3.ParameterInQuery:
+		return &decoded.QueryParams
+	case openapi3.ParameterInHeader:
+		return &decoded.HeaderParams
+	case openapi3.ParameterInCookie:
+		return &decoded.CookieParams
+	}
+	return nil
+}
+
+func (decoded *DecodedRequest) setParam(parameter *openapi3.Parameter, value interface{}) {
+	params := decoded.params(parameter.In)
+	if params == nil {
+		return
+	}
+	if *params == nil {
+		*params = make(map[string]interface{})
+	}
+	(*params)[parameter.Name] = value
+}
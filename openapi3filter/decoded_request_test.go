@@ -0,0 +1,149 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+const decodedRequestSpec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets/{id}:
+    put:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      - name: fields
+        in: query
+        schema:
+          type: array
+          items:
+            type: string
+      - name: filter
+        in: query
+        style: deepObject
+        schema:
+          type: object
+          properties:
+            age:
+              type: integer
+      - name: X-Dry-Run
+        in: header
+        schema:
+          type: boolean
+      - name: session
+        in: cookie
+        content:
+          application/json:
+            schema:
+              type: array
+              items:
+                type: integer
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+      responses:
+        '200':
+          description: OK
+`
+
+func newDecodedRequestTestRequest(t *testing.T) *http.Request {
+	req, err := http.NewRequest(http.MethodPut, "/pets/42?fields=name&fields=age&filter[age]=3", bytes.NewBufferString(`{"name":"Kitty"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dry-Run", "true")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "[1,2]"})
+	return req
+}
+
+func requireDecodedRequest(t *testing.T, decoded *DecodedRequest) {
+	require.NotNil(t, decoded)
+	require.Equal(t, map[string]interface{}{"id": float64(42)}, decoded.PathParams)
+	require.Equal(t, map[string]interface{}{
+		"fields": []interface{}{"name", "age"},
+		"filter": map[string]interface{}{"age": float64(3)},
+	}, decoded.QueryParams)
+	require.Equal(t, map[string]interface{}{"X-Dry-Run": true}, decoded.HeaderParams)
+	require.Equal(t, map[string]interface{}{
+		"session": []interface{}{float64(1), float64(2)},
+	}, decoded.CookieParams)
+	require.Equal(t, map[string]interface{}{"name": "Kitty"}, decoded.Body)
+
+	v, ok := decoded.Param(openapi3.ParameterInPath, "id")
+	require.True(t, ok)
+	require.Equal(t, float64(42), v)
+	_, ok = decoded.Param(openapi3.ParameterInQuery, "id")
+	require.False(t, ok)
+}
+
+func TestValidateRequestDecodedRequest(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(decodedRequestSpec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	req := newDecodedRequestTestRequest(t)
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+	input := &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	}
+	err = ValidateRequest(context.Background(), input)
+	require.NoError(t, err)
+
+	require.Nil(t, DecodedRequestFromContext(req.Context()))
+	requireDecodedRequest(t, input.DecodedRequest())
+
+	// The caller's request is left in place with its body to read again
+	require.Same(t, req, input.Request)
+	data, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"Kitty"}`, string(data))
+}
+
+func TestValidationHandlerDecodedRequest(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(decodedRequestSpec))
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	var decoded *DecodedRequest
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decoded = DecodedRequestFromContext(r.Context())
+	})
+	h := &ValidationHandler{
+		AuthenticationFunc: NoopAuthenticationFunc,
+		ErrorEncoder:       DefaultErrorEncoder,
+		router:             router,
+	}
+
+	w := httptest.NewRecorder()
+	h.Middleware(next).ServeHTTP(w, newDecodedRequestTestRequest(t))
+	require.Equal(t, http.StatusOK, w.Code)
+	requireDecodedRequest(t, decoded)
+}
@@ -13,12 +13,41 @@ type Options struct {
 	// Set ExcludeResponseBody so ValidateResponse skips response body validation
 	ExcludeResponseBody bool
 
+	// Set ExcludeResponseHeaders so ValidateResponse skips response headers validation
+	ExcludeResponseHeaders bool
+
 	// Set IncludeResponseStatus so ValidateResponse fails on response
 	// status not defined in OpenAPI spec
 	IncludeResponseStatus bool
 
 	MultiError bool
 
+	// Set FillDefaults so ValidateRequest fills in absent query, header and cookie
+	// parameters and missing body properties with their schema default values.
+	// The request is rewritten accordingly and the defaults are part of its DecodedRequest.
+	FillDefaults bool
+
+	// Set NegotiateContent so ValidateRequest fails on requests whose Accept header
+	// matches none of the media types of the operation's responses.
+	// The negotiated media type is recorded in the request's DecodedRequest.
+	NegotiateContent bool
+
+	// Set StrictParameters so ValidateRequest fails on query parameters declared
+	// neither by the operation nor by its path item, such as misspelled ones.
+	// An operation overrides it with a boolean x-strict-parameters extension.
+	StrictParameters bool
+
+	// With strict parameters, set StrictCookies to also reject undeclared cookies
+	// and StrictHeaders to also reject undeclared headers, except standard HTTP ones
+	// and those listed in AllowedHeaders.
+	// Names of API keys defined in the security schemes are always accepted.
+	StrictCookies bool
+	StrictHeaders bool
+
+	// AllowedHeaders lists headers accepted by StrictHeaders without being declared,
+	// such as those set by proxies (e.g. "X-Forwarded-For" or "X-Request-Id").
+	AllowedHeaders []string
+
 	// See NoopAuthenticationFunc
 	AuthenticationFunc AuthenticationFunc
 }
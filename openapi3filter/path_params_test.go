@@ -0,0 +1,113 @@
+package openapi3filter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/getkin/kin-openapi/routers/radix"
+	"github.com/stretchr/testify/require"
+)
+
+const pathParamsSpec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /files/{name}.{ext}:
+    get:
+      parameters:
+      - {name: name, in: path, required: true, schema: {type: string}}
+      - {name: ext, in: path, required: true, schema: {type: string}}
+      responses: {'200': {description: OK}}
+  /reports/{year}-{month}:
+    get:
+      parameters:
+      - {name: year, in: path, required: true, schema: {type: integer}}
+      - {name: month, in: path, required: true, schema: {type: integer}}
+      responses: {'200': {description: OK}}
+  /cars{color}{size}:
+    get:
+      parameters:
+      - {name: color, in: path, required: true, style: matrix, explode: true, schema: {type: array, items: {type: string}}}
+      - {name: size, in: path, required: true, style: matrix, schema: {type: integer}}
+      responses: {'200': {description: OK}}
+  /colors/{colors}:
+    get:
+      parameters:
+      - {name: colors, in: path, required: true, style: label, explode: true, schema: {type: array, items: {type: string}}}
+      responses: {'200': {description: OK}}
+`
+
+func TestStyledPathParams(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(pathParamsSpec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path   string
+		params map[string]interface{}
+		legacy bool // Whether the legacy router handles it
+	}{
+		{
+			path:   "/files/report.tar.gz",
+			params: map[string]interface{}{"name": "report.tar", "ext": "gz"},
+			legacy: true,
+		},
+		{
+			path:   "/reports/2021-06",
+			params: map[string]interface{}{"year": 2021.0, "month": 6.0},
+			legacy: true,
+		},
+		{
+			path:   "/cars;color=blue;color=black;size=3",
+			params: map[string]interface{}{"color": []interface{}{"blue", "black"}, "size": 3.0},
+		},
+		{
+			path:   "/colors/.blue.black",
+			params: map[string]interface{}{"colors": []interface{}{"blue", "black"}},
+			legacy: true,
+		},
+	}
+
+	for _, r := range []struct {
+		name      string
+		newRouter func(*openapi3.T, ...routers.Option) (routers.Router, error)
+	}{
+		{"gorillamux", gorillamux.NewRouter},
+		{"legacy", legacyrouter.NewRouter},
+		{"radix", radix.NewRouter},
+	} {
+		router, err := r.newRouter(doc)
+		require.NoError(t, err)
+		for _, tt := range tests {
+			if r.name == "legacy" && !tt.legacy {
+				continue
+			}
+			t.Run(r.name+tt.path, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				route, pathParams, err := router.FindRoute(req)
+				require.NoError(t, err)
+
+				input := &RequestValidationInput{
+					Request:    req,
+					PathParams: pathParams,
+					Route:      route,
+				}
+				err = ValidateRequest(context.Background(), input)
+				require.NoError(t, err)
+				decoded := input.DecodedRequest()
+				require.NotNil(t, decoded)
+				require.Equal(t, tt.params, decoded.PathParams)
+			})
+		}
+	}
+}
@@ -777,6 +777,10 @@ func RegisteredBodyDecoder(contentType string) BodyDecoder {
 
 // RegisterBodyDecoder registers a request body's decoder for a content type.
 //
+// The content type can also be a structured syntax suffix (RFC 6839) such as "+json" or "+xml":
+// the decoder is then used for the media types with that suffix which have no decoder
+// of their own (e.g. "application/vnd.acme.order+json").
+//
 // If a decoder for the specified content type already exists, the function replaces
 // it with the specified decoder.
 // This call is not thread-safe: body decoders should not be created/destroyed by multiple goroutines.
@@ -803,6 +807,16 @@ func UnregisterBodyDecoder(contentType string) {
 
 var headerCT = http.CanonicalHeaderKey("Content-Type")
 
+// structuredSyntaxSuffix returns the suffix of a media type such as "application/merge-patch+json",
+// "+json" here, or an empty string if it has none.
+func structuredSyntaxSuffix(mediaType string) string {
+	i := strings.LastIndexByte(mediaType, '+')
+	if i < 0 || i < strings.IndexByte(mediaType, '/') {
+		return ""
+	}
+	return mediaType[i:]
+}
+
 const prefixUnsupportedCT = "unsupported content type"
 
 // decodeBody returns a decoded body.
@@ -811,6 +825,9 @@ func decodeBody(body io.Reader, header http.Header, schema *openapi3.SchemaRef,
 	contentType := header.Get(headerCT)
 	mediaType := parseMediaType(contentType)
 	decoder, ok := bodyDecoders[mediaType]
+	if !ok {
+		decoder, ok = bodyDecoders[structuredSyntaxSuffix(mediaType)]
+	}
 	if !ok {
 		return nil, &ParseError{
 			Kind:   KindUnsupportedFormat,
@@ -828,6 +845,10 @@ func init() {
 	RegisterBodyDecoder("text/plain", plainBodyDecoder)
 	RegisterBodyDecoder("application/json", jsonBodyDecoder)
 	RegisterBodyDecoder("application/problem+json", jsonBodyDecoder)
+	RegisterBodyDecoder("+json", jsonBodyDecoder)
+	RegisterBodyDecoder("application/xml", xmlBodyDecoder)
+	RegisterBodyDecoder("text/xml", xmlBodyDecoder)
+	RegisterBodyDecoder("+xml", xmlBodyDecoder)
 	RegisterBodyDecoder("application/x-www-form-urlencoded", urlencodedBodyDecoder)
 	RegisterBodyDecoder("multipart/form-data", multipartBodyDecoder)
 	RegisterBodyDecoder("application/octet-stream", FileBodyDecoder)
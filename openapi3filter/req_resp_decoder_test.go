@@ -1009,7 +1009,7 @@ func TestDecodeBody(t *testing.T) {
 	}{
 		{
 			name:    prefixUnsupportedCT,
-			mime:    "application/xml",
+			mime:    "application/yaml",
 			wantErr: &ParseError{Kind: KindUnsupportedFormat},
 		},
 		{
@@ -1225,6 +1225,47 @@ func TestRegisterAndUnregisterBodyDecoder(t *testing.T) {
 	}, err)
 }
 
+func TestStructuredSyntaxSuffixBodyDecoder(t *testing.T) {
+	schema := openapi3.NewObjectSchema().WithProperty("id", openapi3.NewIntegerSchema()).NewRef()
+	encFn := func(string) *openapi3.Encoding { return nil }
+	decode := func(contentType, body string) (interface{}, error) {
+		h := make(http.Header)
+		h.Set(headerCT, contentType)
+		return decodeBody(strings.NewReader(body), h, schema, encFn)
+	}
+
+	got, err := decode("application/vnd.acme.order+json; charset=utf-8", `{"id":1}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"id": 1.0}, got)
+	got, err = decode("application/merge-patch+json", `{"id":null}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"id": nil}, got)
+
+	_, err = decode("application/vnd.acme.order+cbor", "\xa1bid\x01")
+	require.Equal(t, &ParseError{
+		Kind:   KindUnsupportedFormat,
+		Reason: prefixUnsupportedCT + ` "application/vnd.acme.order+cbor"`,
+	}, err)
+
+	// Suffixes are an extension point like content types
+	RegisterBodyDecoder("+cbor", plainBodyDecoder)
+	defer UnregisterBodyDecoder("+cbor")
+	got, err = decode("application/vnd.acme.order+cbor", "\xa1bid\x01")
+	require.NoError(t, err)
+	require.Equal(t, "\xa1bid\x01", got)
+
+	// Content types registered on their own come first
+	RegisterBodyDecoder("application/vnd.acme.order+json", plainBodyDecoder)
+	defer UnregisterBodyDecoder("application/vnd.acme.order+json")
+	got, err = decode("application/vnd.acme.order+json", `{"id":1}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1}`, got)
+
+	data, err := encodeBody(map[string]interface{}{"id": 1}, "application/merge-patch+json")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1}`, string(data))
+}
+
 func matchParseError(got, want error) bool {
 	wErr, ok := want.(*ParseError)
 	if !ok {
@@ -0,0 +1,155 @@
+package openapi3filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BodyEncoder is an interface to encode a decoded body of a request or response
+// back into its wire format.
+type BodyEncoder func(body interface{}) ([]byte, error)
+
+// bodyEncoders contains encoders for supported content types of a body.
+var bodyEncoders = make(map[string]BodyEncoder)
+
+// RegisteredBodyEncoder returns the registered body encoder for the given content type.
+//
+// If no encoder was registered for the given content type, nil is returned.
+// This call is not thread-safe: body encoders should not be created/destroyed by multiple goroutines.
+func RegisteredBodyEncoder(contentType string) BodyEncoder {
+	return bodyEncoders[contentType]
+}
+
+// RegisterBodyEncoder registers a request body's encoder for a content type.
+//
+// As with RegisterBodyDecoder, the content type can be a structured syntax suffix such as "+json".
+//
+// If an encoder for the specified content type already exists, the function replaces
+// it with the specified encoder.
+// This call is not thread-safe: body encoders should not be created/destroyed by multiple goroutines.
+func RegisterBodyEncoder(contentType string, encoder BodyEncoder) {
+	if contentType == "" {
+		panic("contentType is empty")
+	}
+	if encoder == nil {
+		panic("encoder is not defined")
+	}
+	bodyEncoders[contentType] = encoder
+}
+
+// UnregisterBodyEncoder dissociates a body encoder from a content type.
+//
+// Encoding this content type will result in an error.
+// This call is not thread-safe: body encoders should not be created/destroyed by multiple goroutines.
+func UnregisterBodyEncoder(contentType string) {
+	if contentType == "" {
+		panic("contentType is empty")
+	}
+	delete(bodyEncoders, contentType)
+}
+
+// encodeBody returns an encoded body.
+// The function returns ParseError when no encoder is registered for the content type.
+func encodeBody(body interface{}, contentType string) ([]byte, error) {
+	mediaType := parseMediaType(contentType)
+	encoder, ok := bodyEncoders[mediaType]
+	if !ok {
+		encoder, ok = bodyEncoders[structuredSyntaxSuffix(mediaType)]
+	}
+	if !ok {
+		return nil, &ParseError{
+			Kind:   KindUnsupportedFormat,
+			Reason: fmt.Sprintf("%s %q", prefixUnsupportedCT, mediaType),
+		}
+	}
+	return encoder(body)
+}
+
+func init() {
+	RegisterBodyEncoder("application/json", json.Marshal)
+	RegisterBodyEncoder("application/problem+json", json.Marshal)
+	RegisterBodyEncoder("+json", json.Marshal)
+	RegisterBodyEncoder("application/x-www-form-urlencoded", urlencodedBodyEncoder)
+}
+
+func urlencodedBodyEncoder(body interface{}) ([]byte, error) {
+	obj, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unsupported body of type %T", body)
+	}
+	values := make(url.Values, len(obj))
+	for k, v := range obj {
+		if items, ok := v.([]interface{}); ok {
+			for _, item := range items {
+				values.Add(k, formatPrimitive(item))
+			}
+			continue
+		}
+		values.Set(k, formatPrimitive(v))
+	}
+	return []byte(values.Encode()), nil
+}
+
+// encodeParameter serializes the value of a parameter following
+// the given serialization method. Objects exploded in the form style
+// are handled by the caller as they expand into several parameters.
+func encodeParameter(sm *openapi3.SerializationMethod, value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatPrimitive(item))
+		}
+		if sm.Style == openapi3.SerializationForm && sm.Explode {
+			return items
+		}
+		return []string{strings.Join(items, parameterDelimiter(sm))}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, 2*len(v))
+		for _, k := range keys {
+			if sm.Explode {
+				parts = append(parts, k+"="+formatPrimitive(v[k]))
+			} else {
+				parts = append(parts, k, formatPrimitive(v[k]))
+			}
+		}
+		return []string{strings.Join(parts, parameterDelimiter(sm))}
+	default:
+		return []string{formatPrimitive(v)}
+	}
+}
+
+func parameterDelimiter(sm *openapi3.SerializationMethod) string {
+	switch sm.Style {
+	case openapi3.SerializationSpaceDelimited:
+		return " "
+	case openapi3.SerializationPipeDelimited:
+		return "|"
+	default:
+		return ","
+	}
+}
+
+func formatPrimitive(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,242 @@
+package openapi3filter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// RecordReader reads the records of a streamed body one at a time.
+type RecordReader interface {
+	// ReadRecord returns the next record decoded to a primitive, []interface{}
+	// or map[string]interface{}, or io.EOF at the end of the stream.
+	// A *ParseError reports a malformed record: the following ones can still be read.
+	ReadRecord() (interface{}, error)
+}
+
+// StreamDecoder returns a RecordReader of a streamed body, such as the lines
+// of an NDJSON body or the events of a Server-Sent Events one.
+// Each record is validated against the schema of the body's media type.
+type StreamDecoder func(io.Reader, http.Header, *openapi3.SchemaRef) RecordReader
+
+// streamDecoders contains decoders for streamed content types.
+var streamDecoders = make(map[string]StreamDecoder)
+
+// RegisteredStreamDecoder returns the registered stream decoder for the given content type.
+//
+// If no decoder was registered for the given content type, nil is returned.
+// This call is not thread-safe: stream decoders should not be created/destroyed by multiple goroutines.
+func RegisteredStreamDecoder(contentType string) StreamDecoder {
+	return streamDecoders[contentType]
+}
+
+// RegisterStreamDecoder registers a stream decoder for a content type.
+// Bodies of this content type are then validated record by record,
+// without reading them whole, and take precedence over body decoders.
+//
+// If a decoder for the specified content type already exists, the function replaces
+// it with the specified decoder.
+// This call is not thread-safe: stream decoders should not be created/destroyed by multiple goroutines.
+func RegisterStreamDecoder(contentType string, decoder StreamDecoder) {
+	if contentType == "" {
+		panic("contentType is empty")
+	}
+	if decoder == nil {
+		panic("decoder is not defined")
+	}
+	streamDecoders[contentType] = decoder
+}
+
+// UnregisterStreamDecoder dissociates a stream decoder from a content type.
+//
+// This call is not thread-safe: stream decoders should not be created/destroyed by multiple goroutines.
+func UnregisterStreamDecoder(contentType string) {
+	if contentType == "" {
+		panic("contentType is empty")
+	}
+	delete(streamDecoders, contentType)
+}
+
+func init() {
+	RegisterStreamDecoder("application/x-ndjson", NDJSONStreamDecoder)
+	RegisterStreamDecoder("application/jsonl", NDJSONStreamDecoder)
+	RegisterStreamDecoder("application/x-jsonlines", NDJSONStreamDecoder)
+	RegisterStreamDecoder("text/event-stream", EventStreamDecoder)
+}
+
+var _ error = &RecordError{}
+
+// RecordError is a decoding or validation error of a record of a streamed body.
+type RecordError struct {
+	Index int // Position of the record in the stream, from 0
+	Err   error
+}
+
+func (err *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", err.Index, err.Err)
+}
+
+func (err *RecordError) Unwrap() error {
+	return err.Err
+}
+
+// NDJSONStreamDecoder is a stream decoder of newline-delimited JSON (NDJSON or JSON Lines)
+// whose records are the JSON values of the non-blank lines.
+func NDJSONStreamDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef) RecordReader {
+	return &ndjsonReader{r: bufio.NewReader(body)}
+}
+
+type ndjsonReader struct {
+	r *bufio.Reader
+}
+
+func (d *ndjsonReader) ReadRecord() (interface{}, error) {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line = bytes.TrimSpace(line); len(line) == 0 {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(line, &value); err != nil {
+			return nil, &ParseError{Kind: KindInvalidFormat, Value: string(line), Cause: err}
+		}
+		return value, nil
+	}
+}
+
+// EventStreamDecoder is a stream decoder of Server-Sent Events whose records are
+// the data of the events: JSON values, or strings when the schema is a string one.
+// Comments and events without data are skipped.
+func EventStreamDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef) RecordReader {
+	raw := schema != nil && schema.Value != nil && schema.Value.Type == "string"
+	return &eventStreamReader{r: bufio.NewReader(body), raw: raw}
+}
+
+type eventStreamReader struct {
+	r   *bufio.Reader
+	raw bool
+}
+
+func (d *eventStreamReader) ReadRecord() (interface{}, error) {
+	var (
+		data    bytes.Buffer
+		hasData bool
+	)
+	for {
+		line, err := d.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		eof := err == io.EOF
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+
+		if len(line) == 0 {
+			// A blank line dispatches the event, as does the end of the stream
+			if hasData {
+				return d.decode(data.Bytes())
+			}
+			if eof {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], bytes.TrimPrefix(line[i+1:], []byte(" "))
+		}
+		if string(field) == "data" {
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+			hasData = true
+		}
+		// Comments (empty field names) and the other fields are not validated
+
+		if eof {
+			if hasData {
+				return d.decode(data.Bytes())
+			}
+			return nil, io.EOF
+		}
+	}
+}
+
+func (d *eventStreamReader) decode(data []byte) (interface{}, error) {
+	if d.raw {
+		return string(data), nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, &ParseError{Kind: KindInvalidFormat, Value: string(data), Cause: err}
+	}
+	return value, nil
+}
+
+// validateResponseStream validates the records of a streamed response body
+// as they are read, consuming the body.
+func validateResponseStream(input *ResponseValidationInput, contentType *openapi3.MediaType, decoder StreamDecoder, options *Options) error {
+	body := input.Body
+	input.Body = http.NoBody
+	defer body.Close()
+
+	opts := make([]openapi3.SchemaValidationOption, 0, 2) // 2 potential opts here
+	opts = append(opts, openapi3.VisitAsRequest())
+	if options.MultiError {
+		opts = append(opts, openapi3.MultiErrors())
+	}
+
+	var me openapi3.MultiError
+	records := decoder(body, input.Header, contentType.Schema)
+	for i := 0; ; i++ {
+		value, err := records.ReadRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Only malformed records can be skipped, not failures to read the stream
+			_, malformed := err.(*ParseError)
+			err := &ResponseError{
+				Input:  input,
+				Reason: "failed to decode response body",
+				Err:    &RecordError{Index: i, Err: err},
+			}
+			if !malformed || !options.MultiError {
+				if len(me) == 0 {
+					return err
+				}
+				return append(me, err)
+			}
+			me = append(me, err)
+			continue
+		}
+
+		if err := contentType.Schema.Value.VisitJSON(value, opts...); err != nil {
+			err := &ResponseError{
+				Input:  input,
+				Reason: "response body doesn't match the schema",
+				Err:    &RecordError{Index: i, Err: err},
+			}
+			if !options.MultiError {
+				return err
+			}
+			me = append(me, err)
+		}
+	}
+	if len(me) > 0 {
+		return me
+	}
+	return nil
+}
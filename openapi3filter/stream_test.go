@@ -0,0 +1,192 @@
+package openapi3filter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+const streamSpec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/x-ndjson:
+              schema:
+                $ref: '#/components/schemas/Event'
+            text/event-stream:
+              schema:
+                $ref: '#/components/schemas/Event'
+components:
+  schemas:
+    Event:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+`
+
+func readRecords(t *testing.T, records RecordReader) []interface{} {
+	var values []interface{}
+	for {
+		value, err := records.ReadRecord()
+		if err == io.EOF {
+			return values
+		}
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+}
+
+func TestNDJSONStreamDecoder(t *testing.T) {
+	records := NDJSONStreamDecoder(strings.NewReader("{\"id\":1}\n\n  [2]\r\n\"three\""), nil, nil)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"id": 1.0},
+		[]interface{}{2.0},
+		"three",
+	}, readRecords(t, records))
+
+	records = NDJSONStreamDecoder(strings.NewReader("{\n{}\n"), nil, nil)
+	_, err := records.ReadRecord()
+	require.IsType(t, &ParseError{}, err)
+	value, err := records.ReadRecord()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{}, value)
+}
+
+func TestEventStreamDecoder(t *testing.T) {
+	const stream = ": a comment\r\n" +
+		"event: update\r\n" +
+		"data: {\"id\":\r\n" +
+		"data: 1}\r\n" +
+		"\r\n" +
+		"retry: 1000\n" +
+		"\n" +
+		"id: 2\n" +
+		"data:{\"id\":2}"
+	records := EventStreamDecoder(strings.NewReader(stream), nil, nil)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"id": 1.0},
+		map[string]interface{}{"id": 2.0},
+	}, readRecords(t, records))
+
+	// Data is left as is for string schemas
+	records = EventStreamDecoder(strings.NewReader("data: [DONE]\n\n"), nil, openapi3.NewStringSchema().NewRef())
+	require.Equal(t, []interface{}{"[DONE]"}, readRecords(t, records))
+}
+
+func TestValidateResponseStream(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(streamSpec))
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+
+	validate := func(contentType, body string, options *Options) error {
+		header := make(http.Header)
+		header.Set(headerCT, contentType)
+		input := &ResponseValidationInput{
+			RequestValidationInput: &RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+			},
+			Status:  http.StatusOK,
+			Header:  header,
+			Options: options,
+		}
+		input.SetBodyBytes([]byte(body))
+		return ValidateResponse(context.Background(), input)
+	}
+
+	err = validate("application/x-ndjson", "{\"id\":1}\n{\"id\":2}\n", nil)
+	require.NoError(t, err)
+	err = validate("text/event-stream", "data: {\"id\":1}\n\ndata: {\"id\":2}\n\n", nil)
+	require.NoError(t, err)
+
+	err = validate("application/x-ndjson", "{\"id\":1}\n{}\n{\"id\":\"3\"}\n", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `response body doesn't match the schema: record 1: Error at "/id": property "id" is missing`)
+	var recordErr *RecordError
+	require.True(t, errors.As(err.(*ResponseError).Err, &recordErr))
+	require.Equal(t, 1, recordErr.Index)
+
+	err = validate("application/x-ndjson", "{\"id\":1}\n{\n{}\n{\"id\":\"3\"}\n", &Options{MultiError: true})
+	me, ok := err.(openapi3.MultiError)
+	require.True(t, ok)
+	require.Len(t, me, 3)
+	var indexes []int
+	for _, err := range me {
+		require.True(t, errors.As(err.(*ResponseError).Err, &recordErr))
+		indexes = append(indexes, recordErr.Index)
+	}
+	require.Equal(t, []int{1, 2, 3}, indexes)
+	require.Contains(t, me[0].Error(), "failed to decode response body: record 1: ")
+}
+
+// flushRecorder records the body sent at each flush of a streamed response.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (w *flushRecorder) Flush() {
+	w.flushes = append(w.flushes, w.Body.String())
+}
+
+func TestValidationHandlerStreamedResponse(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(streamSpec))
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	events := []string{"data: {\"id\":1}\n\n", "data: {}\n\n"}
+	var reported error
+	h := &ValidationHandler{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, event := range events {
+				w.Write([]byte(event))
+				w.(http.Flusher).Flush()
+			}
+		}),
+		AuthenticationFunc: NoopAuthenticationFunc,
+		ErrorEncoder:       DefaultErrorEncoder,
+		ResponseValidation: ResponseValidationReplace,
+		ResponseErrorReporter: func(r *http.Request, err error) {
+			reported = err
+		},
+		router: router,
+	}
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	// Events are sent as they are written, even invalid ones
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	require.Equal(t, []string{events[0], events[0] + events[1]}, w.flushes)
+	require.Error(t, reported)
+	require.Contains(t, reported.Error(), `record 1: Error at "/id": property "id" is missing`)
+}
@@ -0,0 +1,296 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequestFillDefaults(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets:
+    post:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          default: 20
+      - name: tags
+        in: query
+        schema:
+          type: array
+          items:
+            type: string
+          default: [cat, dog]
+      - name: X-Trace
+        in: header
+        schema:
+          type: boolean
+          default: false
+      - name: session
+        in: cookie
+        schema:
+          type: string
+          default: anonymous
+      - name: sort
+        in: query
+        schema:
+          type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                kind:
+                  type: string
+                  default: cat
+                owner:
+                  type: object
+                  properties:
+                    country:
+                      type: string
+                      default: FR
+                id:
+                  type: integer
+                  readOnly: true
+                  default: 1
+      responses:
+        '200':
+          description: OK
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	newInput := func(query, body string, options *Options) *RequestValidationInput {
+		req, err := http.NewRequest(http.MethodPost, "/pets?"+query, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		input := newInput("", `{"name":"Kitty"}`, nil)
+		err := ValidateRequest(context.Background(), input)
+		require.NoError(t, err)
+
+		req := input.Request
+		require.Empty(t, req.URL.RawQuery)
+		require.Empty(t, req.Header.Get("X-Trace"))
+		data, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"Kitty"}`, string(data))
+
+		decoded := input.DecodedRequest()
+		require.NotNil(t, decoded)
+		require.Empty(t, decoded.QueryParams)
+		require.Empty(t, decoded.HeaderParams)
+		require.Empty(t, decoded.CookieParams)
+	})
+
+	t.Run("fills in absent values", func(t *testing.T) {
+		input := newInput("sort=name", `{"name":"Kitty","owner":{}}`, &Options{FillDefaults: true})
+		err := ValidateRequest(context.Background(), input)
+		require.NoError(t, err)
+
+		req := input.Request
+		require.Equal(t, []string{"20"}, req.URL.Query()["limit"])
+		require.Equal(t, []string{"cat", "dog"}, req.URL.Query()["tags"])
+		require.Equal(t, []string{"name"}, req.URL.Query()["sort"])
+		require.Equal(t, "false", req.Header.Get("X-Trace"))
+		cookie, err := req.Cookie("session")
+		require.NoError(t, err)
+		require.Equal(t, "anonymous", cookie.Value)
+
+		data, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"Kitty","kind":"cat","owner":{"country":"FR"}}`, string(data))
+		require.Equal(t, int64(len(data)), req.ContentLength)
+
+		decoded := input.DecodedRequest()
+		require.NotNil(t, decoded)
+		require.Equal(t, map[string]interface{}{
+			"limit": float64(20),
+			"tags":  []interface{}{"cat", "dog"},
+			"sort":  "name",
+		}, decoded.QueryParams)
+		require.Equal(t, map[string]interface{}{"X-Trace": false}, decoded.HeaderParams)
+		require.Equal(t, map[string]interface{}{"session": "anonymous"}, decoded.CookieParams)
+		require.Equal(t, map[string]interface{}{
+			"name":  "Kitty",
+			"kind":  "cat",
+			"owner": map[string]interface{}{"country": "FR"},
+		}, decoded.Body)
+	})
+
+	t.Run("keeps provided values", func(t *testing.T) {
+		input := newInput("limit=5", `{"name":"Rex","kind":"dog"}`, &Options{FillDefaults: true})
+		err := ValidateRequest(context.Background(), input)
+		require.NoError(t, err)
+
+		req := input.Request
+		require.Equal(t, []string{"5"}, req.URL.Query()["limit"])
+		data, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"Rex","kind":"dog"}`, string(data))
+
+		decoded := input.DecodedRequest()
+		require.NotNil(t, decoded)
+		require.Equal(t, float64(5), decoded.QueryParams["limit"])
+	})
+}
+
+func TestValidateRequestFillDefaultsCopiesDefaults(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets:
+    post:
+      parameters:
+      - name: filter
+        in: query
+        style: deepObject
+        explode: true
+        schema:
+          type: object
+          properties:
+            kind:
+              type: string
+          default: {kind: cat}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                settings:
+                  type: object
+                  properties:
+                    theme:
+                      type: string
+                      default: dark
+                  default: {}
+      responses:
+        '200':
+          description: OK
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func() (*http.Request, *DecodedRequest, error) {
+		req, err := http.NewRequest(http.MethodPost, "/pets", bytes.NewBufferString(`{}`))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		route, pathParams, err := router.FindRoute(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		input := &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &Options{FillDefaults: true},
+		}
+		err = ValidateRequest(context.Background(), input)
+		return req, input.DecodedRequest(), err
+	}
+
+	// Run with -race: requests must not share the document's defaults
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := validate()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	req, decoded, err := validate()
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"settings": map[string]interface{}{"theme": "dark"},
+	}, decoded.Body)
+	require.Equal(t, map[string]interface{}{"kind": "cat"}, decoded.QueryParams["filter"])
+	require.Equal(t, []string{"cat"}, req.URL.Query()["filter[kind]"])
+
+	settings := doc.Paths["/pets"].Post.RequestBody.Value.Content.Get("application/json").Schema.Value.Properties["settings"].Value
+	require.Equal(t, map[string]interface{}{}, settings.Default)
+	filter := doc.Paths["/pets"].Post.Parameters.GetByInAndName("query", "filter")
+	require.Equal(t, map[string]interface{}{"kind": "cat"}, filter.Schema.Value.Default)
+}
+
+func TestSetParameterDefaultEncodesEveryValue(t *testing.T) {
+	explode := true
+	newParameter := func(in, name string) *openapi3.Parameter {
+		return &openapi3.Parameter{In: in, Name: name, Style: openapi3.SerializationForm, Explode: &explode}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/pets", nil)
+	require.NoError(t, err)
+	input := &RequestValidationInput{Request: req}
+
+	setParameterDefault(input, newParameter(openapi3.ParameterInCookie, "allergies"), []interface{}{})
+	setParameterDefault(input, newParameter(openapi3.ParameterInCookie, "flavours"), []interface{}{"sweet", "salty"})
+	setParameterDefault(input, newParameter(openapi3.ParameterInHeader, "X-Empty"), []interface{}{})
+	setParameterDefault(input, newParameter(openapi3.ParameterInHeader, "X-Tags"), []interface{}{"a", "b"})
+	setParameterDefault(input, newParameter(openapi3.ParameterInQuery, "ids"), []interface{}{})
+
+	var flavours []string
+	for _, cookie := range req.Cookies() {
+		require.NotEqual(t, "allergies", cookie.Name)
+		if cookie.Name == "flavours" {
+			flavours = append(flavours, cookie.Value)
+		}
+	}
+	require.Equal(t, []string{"sweet", "salty"}, flavours)
+	require.NotContains(t, req.Header, "X-Empty")
+	require.Equal(t, []string{"a", "b"}, req.Header.Values("X-Tags"))
+	require.Empty(t, req.URL.RawQuery)
+}
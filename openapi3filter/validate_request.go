@@ -3,11 +3,14 @@ package openapi3filter
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -19,10 +22,19 @@ var ErrAuthenticationServiceMissing = errors.New("missing AuthenticationFunc")
 // ErrInvalidRequired is returned when a required value of a parameter or request body is not defined.
 var ErrInvalidRequired = errors.New("value is required but missing")
 
+// ErrUndeclaredParameter is returned in strict mode when a request has a parameter
+// its operation does not declare.
+var ErrUndeclaredParameter = errors.New("parameter is not declared")
+
+// StrictParametersExtension is the operation extension overriding Options.StrictParameters.
+const StrictParametersExtension = "x-strict-parameters"
+
 // ValidateRequest is used to validate the given input according to previous
 // loaded OpenAPIv3 spec. If the input does not match the OpenAPIv3 spec, a
 // non-nil error will be returned.
 //
+// The decoded parameters and body are then available from input.DecodedRequest.
+//
 // Note: One can tune the behavior of uniqueItems: true verification
 // by registering a custom function with openapi3.RegisterArrayUniqueItemsChecker
 func ValidateRequest(ctx context.Context, input *RequestValidationInput) error {
@@ -69,6 +81,27 @@ func ValidateRequest(ctx context.Context, input *RequestValidationInput) error {
 		}
 	}
 
+	// Parameters declared nowhere
+	if strictParameters(options, operation) {
+		if errs := validateUndeclaredParameters(input, options); len(errs) != 0 {
+			if !options.MultiError {
+				return errs[0]
+			}
+			me = append(me, errs...)
+		}
+	}
+
+	// Accept
+	if options.NegotiateContent {
+		if err = validateAccept(input); err != nil && !options.MultiError {
+			return err
+		}
+
+		if err != nil {
+			me = append(me, err)
+		}
+	}
+
 	// RequestBody
 	requestBody := operation.RequestBody
 	if requestBody != nil && !options.ExcludeRequestBody {
@@ -137,6 +170,13 @@ func ValidateParameter(ctx context.Context, input *RequestValidationInput, param
 		}
 		schema = parameter.Schema.Value
 	}
+	if options.FillDefaults && schema != nil && schema.Default != nil && isAbsent(value) {
+		value = copyDefault(schema.Default)
+		setParameterDefault(input, parameter, value)
+	}
+	if !isAbsent(value) {
+		decodedRequest(input).setParam(parameter, value)
+	}
 	// Validate a parameter's value.
 	if value == nil {
 		if parameter.Required {
@@ -160,6 +200,213 @@ func ValidateParameter(ctx context.Context, input *RequestValidationInput, param
 	return nil
 }
 
+// isAbsent reports whether a decoded parameter value is missing,
+// as array and object decoders return typed nils for absent parameters.
+func isAbsent(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []interface{}:
+		return v == nil
+	case map[string]interface{}:
+		return v == nil
+	}
+	return false
+}
+
+// setParameterDefault rewrites input's request so the parameter carries the given default value.
+func setParameterDefault(input *RequestValidationInput, parameter *openapi3.Parameter, value interface{}) {
+	sm, err := parameter.SerializationMethod()
+	if err != nil {
+		return
+	}
+	req := input.Request
+	switch parameter.In {
+	case openapi3.ParameterInQuery:
+		q := input.GetQueryParams()
+		if obj, ok := value.(map[string]interface{}); ok && (sm.Explode || sm.Style == openapi3.SerializationDeepObject) {
+			for k, v := range obj {
+				name := k
+				if sm.Style == openapi3.SerializationDeepObject {
+					name = parameter.Name + "[" + k + "]"
+				}
+				q.Set(name, formatPrimitive(v))
+			}
+		} else {
+			q[parameter.Name] = encodeParameter(sm, value)
+		}
+		req.URL.RawQuery = q.Encode()
+	case openapi3.ParameterInHeader:
+		req.Header.Del(parameter.Name)
+		for _, v := range encodeParameter(sm, value) {
+			req.Header.Add(parameter.Name, v)
+		}
+	case openapi3.ParameterInCookie:
+		for _, v := range encodeParameter(sm, value) {
+			req.AddCookie(&http.Cookie{Name: parameter.Name, Value: v})
+		}
+	}
+}
+
+// copyDefault returns a copy of a schema's default value, typed as decoded from JSON,
+// so that requests neither share nor change the document's value.
+func copyDefault(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var copied interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return value
+	}
+	return copied
+}
+
+// strictParameters tells whether undeclared parameters are rejected for the operation.
+func strictParameters(options *Options, operation *openapi3.Operation) bool {
+	if raw, ok := operation.Extensions[StrictParametersExtension].(json.RawMessage); ok {
+		var strict bool
+		if err := json.Unmarshal(raw, &strict); err == nil {
+			return strict
+		}
+	}
+	return options.StrictParameters
+}
+
+// standardRequestHeaders are accepted by Options.StrictHeaders without being declared.
+var standardRequestHeaders = map[string]struct{}{
+	"Accept": {}, "Accept-Charset": {}, "Accept-Encoding": {}, "Accept-Language": {},
+	"Access-Control-Request-Headers": {}, "Access-Control-Request-Method": {},
+	"Authorization": {}, "Cache-Control": {}, "Connection": {}, "Content-Encoding": {},
+	"Content-Language": {}, "Content-Length": {}, "Content-Type": {}, "Cookie": {},
+	"Date": {}, "Dnt": {}, "Expect": {}, "Forwarded": {}, "Host": {},
+	"If-Match": {}, "If-Modified-Since": {}, "If-None-Match": {}, "If-Range": {}, "If-Unmodified-Since": {},
+	"Keep-Alive": {}, "Max-Forwards": {}, "Origin": {}, "Pragma": {}, "Proxy-Authorization": {},
+	"Range": {}, "Referer": {}, "Te": {}, "Trailer": {}, "Transfer-Encoding": {},
+	"Upgrade": {}, "Upgrade-Insecure-Requests": {}, "User-Agent": {}, "Via": {},
+}
+
+// validateUndeclaredParameters returns an error for each query parameter,
+// and cookie or header in strict mode, declared neither by the operation nor its path item.
+func validateUndeclaredParameters(input *RequestValidationInput, options *Options) []error {
+	declared := map[string]map[string]struct{}{
+		openapi3.ParameterInQuery:  {},
+		openapi3.ParameterInHeader: {},
+		openapi3.ParameterInCookie: {},
+	}
+	var queryPrefixes []string
+	anyQuery := false
+	declare := func(in, name string) {
+		if names, ok := declared[in]; ok {
+			if in == openapi3.ParameterInHeader {
+				name = http.CanonicalHeaderKey(name)
+			}
+			names[name] = struct{}{}
+		}
+	}
+
+	route := input.Route
+	for _, parameters := range []openapi3.Parameters{route.PathItem.Parameters, route.Operation.Parameters} {
+		for _, ref := range parameters {
+			parameter := ref.Value
+			if parameter == nil {
+				continue
+			}
+			declare(parameter.In, parameter.Name)
+			if parameter.In != openapi3.ParameterInQuery || parameter.Schema == nil || parameter.Schema.Value == nil {
+				continue
+			}
+			schema := parameter.Schema.Value
+			sm, err := parameter.SerializationMethod()
+			if err != nil || schema.Type != "object" {
+				continue
+			}
+			switch {
+			case sm.Style == openapi3.SerializationDeepObject:
+				queryPrefixes = append(queryPrefixes, parameter.Name+"[")
+			case sm.Style == openapi3.SerializationForm && sm.Explode:
+				// The properties of the object are parameters on their own
+				for name := range schema.Properties {
+					declare(openapi3.ParameterInQuery, name)
+				}
+				if allowed := schema.AdditionalPropertiesAllowed; allowed == nil || *allowed || schema.AdditionalProperties != nil {
+					anyQuery = true
+				}
+			}
+		}
+	}
+	if route.Spec != nil {
+		for _, ref := range route.Spec.Components.SecuritySchemes {
+			if scheme := ref.Value; scheme != nil && scheme.Type == "apiKey" {
+				declare(scheme.In, scheme.Name)
+			}
+		}
+	}
+
+	var errs []error
+	undeclared := func(in string, names []string) {
+		sort.Strings(names)
+		for _, name := range names {
+			errs = append(errs, &RequestError{
+				Input:     input,
+				Parameter: &openapi3.Parameter{Name: name, In: in},
+				Err:       ErrUndeclaredParameter,
+			})
+		}
+	}
+
+	if !anyQuery {
+		var names []string
+	query:
+		for name := range input.GetQueryParams() {
+			if _, ok := declared[openapi3.ParameterInQuery][name]; ok {
+				continue
+			}
+			for _, prefix := range queryPrefixes {
+				if strings.HasPrefix(name, prefix) {
+					continue query
+				}
+			}
+			names = append(names, name)
+		}
+		undeclared(openapi3.ParameterInQuery, names)
+	}
+
+	if options.StrictHeaders {
+		for _, name := range options.AllowedHeaders {
+			declare(openapi3.ParameterInHeader, name)
+		}
+		var names []string
+		for name := range input.Request.Header {
+			if _, ok := declared[openapi3.ParameterInHeader][name]; ok {
+				continue
+			}
+			if _, ok := standardRequestHeaders[name]; ok || strings.HasPrefix(name, "Sec-") {
+				continue
+			}
+			names = append(names, name)
+		}
+		undeclared(openapi3.ParameterInHeader, names)
+	}
+
+	if options.StrictCookies {
+		var names []string
+		seen := make(map[string]struct{})
+		for _, cookie := range input.Request.Cookies() {
+			if _, ok := declared[openapi3.ParameterInCookie][cookie.Name]; ok {
+				continue
+			}
+			if _, ok := seen[cookie.Name]; !ok {
+				seen[cookie.Name] = struct{}{}
+				names = append(names, cookie.Name)
+			}
+		}
+		undeclared(openapi3.ParameterInCookie, names)
+	}
+
+	return errs
+}
+
 const prefixInvalidCT = "header Content-Type has unexpected value"
 
 // ValidateRequestBody validates data of a request's body.
@@ -231,11 +478,15 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 		}
 	}
 
-	opts := make([]openapi3.SchemaValidationOption, 0, 2) // 2 potential opts here
+	defaultsSet := false
+	opts := make([]openapi3.SchemaValidationOption, 0, 3) // 3 potential opts here
 	opts = append(opts, openapi3.VisitAsRequest())
 	if options.MultiError {
 		opts = append(opts, openapi3.MultiErrors())
 	}
+	if options.FillDefaults {
+		opts = append(opts, openapi3.DefaultsSet(func() { defaultsSet = true }))
+	}
 
 	// Validate JSON with the schema
 	if err := contentType.Schema.Value.VisitJSON(value, opts...); err != nil {
@@ -246,6 +497,24 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 			Err:         err,
 		}
 	}
+
+	if defaultsSet {
+		if data, err = encodeBody(value, inputMIME); err != nil {
+			return &RequestError{
+				Input:       input,
+				RequestBody: requestBody,
+				Reason:      "failed to encode request body with defaults",
+				Err:         err,
+			}
+		}
+		// Put the data with defaults back into the input
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+		req.ContentLength = int64(len(data))
+		if req.Header.Get("Content-Length") != "" {
+			req.Header.Set("Content-Length", strconv.Itoa(len(data)))
+		}
+	}
+	decodedRequest(input).Body = value
 	return nil
 }
 
@@ -26,6 +26,14 @@ type RequestValidationInput struct {
 	Route        *routers.Route
 	Options      *Options
 	ParamDecoder ContentParameterDecoder
+
+	decoded *DecodedRequest
+}
+
+// DecodedRequest returns the parameters and body decoded while validating the input,
+// or nil if nothing was decoded.
+func (input *RequestValidationInput) DecodedRequest() *DecodedRequest {
+	return input.decoded
 }
 
 func (input *RequestValidationInput) GetQueryParams() url.Values {
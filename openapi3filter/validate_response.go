@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -15,6 +16,10 @@ import (
 // loaded OpenAPIv3 spec. If the input does not match the OpenAPIv3 spec, a
 // non-nil error will be returned.
 //
+// Bodies of a content type with a registered StreamDecoder are validated
+// record by record as they are read: they are consumed rather than put back
+// into the input, see RegisterStreamDecoder.
+//
 // Note: One can tune the behavior of uniqueItems: true verification
 // by registering a custom function with openapi3.RegisterArrayUniqueItemsChecker
 func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error {
@@ -61,13 +66,129 @@ func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error
 		return &ResponseError{Input: input, Reason: "response has not been resolved"}
 	}
 
-	if options.ExcludeResponseBody {
-		// A user turned off validation of a response's body.
+	var me openapi3.MultiError
+	if !options.ExcludeResponseHeaders {
+		if errs := validateResponseHeaders(input, response, options); len(errs) > 0 {
+			if !options.MultiError {
+				return errs[0]
+			}
+			me = append(me, errs...)
+		}
+	}
+
+	if !options.ExcludeResponseBody {
+		if err := validateResponseBody(input, response, options); err != nil {
+			if len(me) == 0 {
+				return err
+			}
+			me = append(me, err)
+		}
+	}
+
+	if len(me) > 0 {
+		return me
+	}
+	return nil
+}
+
+// validateResponseHeaders validates the response's headers against the
+// headers declared by the response object, in a deterministic order.
+// It returns one error per invalid header.
+func validateResponseHeaders(input *ResponseValidationInput, response *openapi3.Response, options *Options) []error {
+	names := make([]string, 0, len(response.Headers))
+	for name := range response.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		// A header named "Content-Type" SHALL be ignored.
+		// See https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.3.md#responseObject
+		if http.CanonicalHeaderKey(name) == headerCT {
+			continue
+		}
+		headerRef := response.Headers[name]
+		if headerRef == nil || headerRef.Value == nil {
+			errs = append(errs, &ResponseError{
+				Input:  input,
+				Reason: fmt.Sprintf("response header %q has not been resolved", name),
+			})
+			continue
+		}
+		if err := validateResponseHeader(input, name, headerRef.Value, options); err != nil {
+			errs = append(errs, err)
+			if !options.MultiError {
+				break
+			}
+		}
+	}
+	return errs
+}
+
+func validateResponseHeader(input *ResponseValidationInput, name string, header *openapi3.Header, options *Options) error {
+	var (
+		value  interface{}
+		schema *openapi3.Schema
+		err    error
+	)
+	if header.Content != nil {
+		// Content headers are decoded the same way content parameters are.
+		param := header.Parameter
+		param.Name, param.In = name, openapi3.ParameterInHeader
+		if raw := input.Header.Get(http.CanonicalHeaderKey(name)); raw != "" {
+			value, schema, err = defaultContentParameterDecoder(&param, []string{raw})
+		}
+	} else if header.Schema != nil {
+		var sm *openapi3.SerializationMethod
+		if sm, err = header.SerializationMethod(); err == nil {
+			dec := &headerParamDecoder{header: input.Header}
+			value, err = decodeValue(dec, name, sm, header.Schema, header.Required)
+		}
+		schema = header.Schema.Value
+	}
+	if err != nil {
+		return &ResponseError{
+			Input:  input,
+			Reason: fmt.Sprintf("response header %q is invalid", name),
+			Err:    err,
+		}
+	}
+
+	if value == nil {
+		if header.Required {
+			return &ResponseError{
+				Input:  input,
+				Reason: fmt.Sprintf("response header %q is missing", name),
+				Err:    ErrInvalidRequired,
+			}
+		}
+		return nil
+	}
+	if schema == nil {
+		// A header's schema is not defined so skip validation of its value.
 		return nil
 	}
 
+	var opts []openapi3.SchemaValidationOption
+	if options.MultiError {
+		opts = append(opts, openapi3.MultiErrors())
+	}
+	if err = schema.VisitJSON(value, opts...); err != nil {
+		return &ResponseError{
+			Input:  input,
+			Reason: fmt.Sprintf("response header %q doesn't match the schema", name),
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// validateResponseBody validates the response's body against the content
+// declared by the response object.
+func validateResponseBody(input *ResponseValidationInput, response *openapi3.Response, options *Options) error {
 	content := response.Content
-	if len(content) == 0 || options.ExcludeResponseBody {
+	if len(content) == 0 {
 		// An operation does not contains a validation schema for responses with this status code.
 		return nil
 	}
@@ -86,6 +207,10 @@ func ValidateResponse(ctx context.Context, input *ResponseValidationInput) error
 		return nil
 	}
 
+	if decoder := streamDecoders[parseMediaType(inputMIME)]; decoder != nil {
+		return validateResponseStream(input, contentType, decoder, options)
+	}
+
 	// Read response's body.
 	body := input.Body
 
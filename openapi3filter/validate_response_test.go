@@ -0,0 +1,159 @@
+package openapi3filter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateResponseHeaders(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /rate:
+    get:
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-RateLimit-Remaining:
+              required: true
+              schema:
+                type: integer
+                minimum: 0
+            X-Retry-Codes:
+              schema:
+                type: array
+                items:
+                  type: integer
+                  maximum: 599
+            X-Meta:
+              content:
+                application/json:
+                  schema:
+                    type: object
+                    required: [region]
+                    properties:
+                      region:
+                        type: string
+            Content-Type:
+              required: true
+              schema:
+                type: string
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	httpReq, err := http.NewRequest(http.MethodGet, "/rate", nil)
+	require.NoError(t, err)
+	route, pathParams, err := router.FindRoute(httpReq)
+	require.NoError(t, err)
+
+	validate := func(header http.Header, options *Options) error {
+		return ValidateResponse(context.Background(), &ResponseValidationInput{
+			RequestValidationInput: &RequestValidationInput{
+				Request:    httpReq,
+				PathParams: pathParams,
+				Route:      route,
+			},
+			Status:  http.StatusOK,
+			Header:  header,
+			Options: options,
+		})
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		fail   bool
+	}{
+		{
+			name:   "valid",
+			header: http.Header{"X-Ratelimit-Remaining": {"10"}},
+		},
+		{
+			name: "valid with optional headers",
+			header: http.Header{
+				"X-Ratelimit-Remaining": {"10"},
+				"X-Retry-Codes":         {"429,503"},
+				"X-Meta":                {`{"region":"eu"}`},
+			},
+		},
+		{
+			name: "missing required header",
+			fail: true,
+		},
+		{
+			name:   "not an integer",
+			header: http.Header{"X-Ratelimit-Remaining": {"many"}},
+			fail:   true,
+		},
+		{
+			name:   "below minimum",
+			header: http.Header{"X-Ratelimit-Remaining": {"-1"}},
+			fail:   true,
+		},
+		{
+			name: "invalid array item",
+			header: http.Header{
+				"X-Ratelimit-Remaining": {"10"},
+				"X-Retry-Codes":         {"429,600"},
+			},
+			fail: true,
+		},
+		{
+			name: "invalid content header",
+			header: http.Header{
+				"X-Ratelimit-Remaining": {"10"},
+				"X-Meta":                {`{}`},
+			},
+			fail: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			err := validate(header, nil)
+			if !tt.fail {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.IsType(t, &ResponseError{}, err)
+
+			err = validate(header, &Options{ExcludeResponseHeaders: true})
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("multi error", func(t *testing.T) {
+		err := validate(http.Header{
+			"X-Retry-Codes": {"429,600"},
+			"X-Meta":        {`{}`},
+		}, &Options{MultiError: true})
+		require.Error(t, err)
+		me, ok := err.(openapi3.MultiError)
+		require.True(t, ok)
+		require.Len(t, me, 3)
+		for _, e := range me {
+			require.IsType(t, &ResponseError{}, e)
+		}
+		require.Equal(t, ErrInvalidRequired, me[1].(*ResponseError).Err)
+	})
+}
@@ -0,0 +1,146 @@
+package openapi3filter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequestStrictParameters(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+paths:
+  /pets:
+    parameters:
+    - name: X-Tenant
+      in: header
+      schema:
+        type: string
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+      - name: filter
+        in: query
+        style: deepObject
+        explode: true
+        schema:
+          type: object
+          properties:
+            kind:
+              type: string
+      - name: session
+        in: cookie
+        schema:
+          type: string
+      responses:
+        '200':
+          description: OK
+  /legacy:
+    get:
+      x-strict-parameters: false
+      responses:
+        '200':
+          description: OK
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	validate := func(req *http.Request, options *Options) error {
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return ValidateRequest(context.Background(), &RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+	}
+	newRequest := func(target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("User-Agent", "test")
+		req.Header.Set("X-Tenant", "acme")
+		req.Header.Set("X-API-Key", "secret")
+		req.Header.Set("X-Request-Id", "42")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+		req.AddCookie(&http.Cookie{Name: "tracking", Value: "xyz"})
+		return req
+	}
+	options := func(o Options) *Options {
+		o.AuthenticationFunc = NoopAuthenticationFunc
+		return &o
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		err := validate(newRequest("/pets?limt=10"), options(Options{}))
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects undeclared query parameters", func(t *testing.T) {
+		err := validate(newRequest("/pets?limit=10&filter[kind]=cat"), options(Options{StrictParameters: true}))
+		require.NoError(t, err)
+
+		err = validate(newRequest("/pets?limt=10&filter[kind]=cat"), options(Options{StrictParameters: true}))
+		require.EqualError(t, err, `parameter "limt" in query has an error: parameter is not declared`)
+		require.True(t, errors.Is(err.(*RequestError).Err, ErrUndeclaredParameter))
+	})
+
+	t.Run("rejects undeclared headers and cookies", func(t *testing.T) {
+		err := validate(newRequest("/pets?x=1"), options(Options{
+			StrictParameters: true,
+			StrictHeaders:    true,
+			StrictCookies:    true,
+			MultiError:       true,
+		}))
+		me, ok := err.(openapi3.MultiError)
+		require.True(t, ok)
+		require.Len(t, me, 3)
+		require.EqualError(t, me[0], `parameter "x" in query has an error: parameter is not declared`)
+		require.EqualError(t, me[1], `parameter "X-Request-Id" in header has an error: parameter is not declared`)
+		require.EqualError(t, me[2], `parameter "tracking" in cookie has an error: parameter is not declared`)
+
+		err = validate(newRequest("/pets"), options(Options{
+			StrictParameters: true,
+			StrictHeaders:    true,
+			AllowedHeaders:   []string{"x-request-id"},
+		}))
+		require.NoError(t, err)
+	})
+
+	t.Run("operation extension overrides options", func(t *testing.T) {
+		err := validate(newRequest("/legacy?anything=1"), options(Options{StrictParameters: true}))
+		require.NoError(t, err)
+	})
+
+	t.Run("encodes as a bad request", func(t *testing.T) {
+		err := validate(newRequest("/pets?limt=10"), options(Options{StrictParameters: true}))
+		rec := httptest.NewRecorder()
+		enc := &ValidationErrorEncoder{Encoder: (ErrorEncoder)(DefaultErrorEncoder)}
+		enc.Encode(context.Background(), err, rec)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Contains(t, rec.Body.String(), `parameter "limt" in query is not declared`)
+	})
+}
@@ -2,6 +2,7 @@ package openapi3filter
 
 import (
 	"bytes"
+	"net/http"
 	"strconv"
 )
 
@@ -21,6 +22,8 @@ type ValidationError struct {
 	Detail string `json:"detail,omitempty"`
 	// An object containing references to the source of the error
 	Source *ValidationErrorSource `json:"source,omitempty"`
+	// Headers of the HTTP response, such as the Allow header of a 405.
+	Header http.Header `json:"-"`
 }
 
 // ValidationErrorSource struct
@@ -83,3 +86,8 @@ func (e *ValidationError) Error() string {
 func (e *ValidationError) StatusCode() int {
 	return e.Status
 }
+
+// Headers implements the Headerer interface for DefaultErrorEncoder
+func (e *ValidationError) Headers() http.Header {
+	return e.Header
+}
@@ -34,6 +34,10 @@ func (enc *ValidationErrorEncoder) Encode(ctx context.Context, err error, w http
 		cErr = convertBasicRequestError(e)
 	} else if e.Err == ErrInvalidRequired {
 		cErr = convertErrInvalidRequired(e)
+	} else if e.Err == ErrUndeclaredParameter {
+		cErr = convertErrUndeclaredParameter(e)
+	} else if e.Err == ErrNotAcceptable {
+		cErr = &ValidationError{Status: http.StatusNotAcceptable, Title: e.Reason}
 	} else if innerErr, ok := e.Err.(*ParseError); ok {
 		cErr = convertParseError(e, innerErr)
 	} else if innerErr, ok := e.Err.(*openapi3.SchemaError); ok {
@@ -52,7 +56,11 @@ func convertRouteError(e *routers.RouteError) *ValidationError {
 	if e.Error() == routers.ErrMethodNotAllowed.Error() {
 		status = http.StatusMethodNotAllowed
 	}
-	return &ValidationError{Status: status, Title: e.Error()}
+	cErr := &ValidationError{Status: status, Title: e.Error()}
+	if len(e.AllowedMethods) != 0 {
+		cErr.Header = http.Header{"Allow": {strings.Join(e.AllowedMethods, ", ")}}
+	}
+	return cErr
 }
 
 func convertBasicRequestError(e *RequestError) *ValidationError {
@@ -87,6 +95,13 @@ func convertErrInvalidRequired(e *RequestError) *ValidationError {
 	}
 }
 
+func convertErrUndeclaredParameter(e *RequestError) *ValidationError {
+	return &ValidationError{
+		Status: http.StatusBadRequest,
+		Title:  fmt.Sprintf("parameter %q in %s is not declared", e.Parameter.Name, e.Parameter.In),
+	}
+}
+
 func convertParseError(e *RequestError, innerErr *ParseError) *ValidationError {
 	// We treat path params of the wrong type like a 404 instead of a 400
 	if innerErr.Kind == KindInvalidFormat && e.Parameter != nil && e.Parameter.In == "path" {
@@ -69,8 +69,11 @@ func getValidationTests(t *testing.T) []*validationTest {
 	noContentTypeNeeded := newPetstoreRequest(t, http.MethodGet, "/pet/findByStatus?status=sold", nil)
 	noContentTypeNeeded.Header.Del(headerCT)
 
-	unknownContentType := newPetstoreRequest(t, http.MethodPost, "/pet", bytes.NewBufferString(`{}`))
-	unknownContentType.Header.Set(headerCT, "application/xml")
+	malformedXML := newPetstoreRequest(t, http.MethodPost, "/pet", bytes.NewBufferString(`{}`))
+	malformedXML.Header.Set(headerCT, "application/xml")
+
+	missingXMLElement := newPetstoreRequest(t, http.MethodPost, "/pet", bytes.NewBufferString(`<Pet><name>Bahama</name></Pet>`))
+	missingXMLElement.Header.Set(headerCT, "application/xml")
 
 	unsupportedContentType := newPetstoreRequest(t, http.MethodPost, "/pet", bytes.NewBufferString(`{}`))
 	unsupportedContentType.Header.Set(headerCT, "text/plain")
@@ -105,10 +108,9 @@ func getValidationTests(t *testing.T) []*validationTest {
 				r: badMethod,
 			},
 			wantErrReason: routers.ErrMethodNotAllowed.Error(),
-			// TODO: By HTTP spec, this should have an Allow header with what is allowed
-			// but kin-openapi doesn't provide us the requested method or path, so impossible to provide details
 			wantErrResponse: &ValidationError{Status: http.StatusMethodNotAllowed,
-				Title: routers.ErrMethodNotAllowed.Error()},
+				Title:  routers.ErrMethodNotAllowed.Error(),
+				Header: http.Header{"Allow": {"PATCH, POST"}}},
 		},
 		{
 			name: "error - missing body on POST",
@@ -143,15 +145,26 @@ func getValidationTests(t *testing.T) []*validationTest {
 				Title: "header Content-Type is required"},
 		},
 		{
-			name: "error - unknown content-type on POST",
+			name: "error - malformed XML on POST",
 			args: validationArgs{
-				r: unknownContentType,
+				r: malformedXML,
 			},
-			wantErrReason:      "failed to decode request body",
-			wantErrParseKind:   KindUnsupportedFormat,
-			wantErrParseReason: prefixUnsupportedCT + ` "application/xml"`,
-			wantErrResponse: &ValidationError{Status: http.StatusUnsupportedMediaType,
-				Title: prefixUnsupportedCT + ` "application/xml"`},
+			wantErrReason:    "failed to decode request body",
+			wantErrParseKind: KindInvalidFormat,
+			wantErrResponse:  &ValidationError{Status: http.StatusBadRequest},
+		},
+		{
+			name: "error - missing required XML element on POST",
+			args: validationArgs{
+				r: missingXMLElement,
+			},
+			wantErrReason:       "doesn't match the schema",
+			wantErrSchemaReason: `property "photoUrls" is missing`,
+			wantErrSchemaValue:  map[string]string{"name": "Bahama"},
+			wantErrSchemaPath:   "/photoUrls",
+			wantErrResponse: &ValidationError{Status: http.StatusUnprocessableEntity,
+				Title:  `property "photoUrls" is missing`,
+				Source: &ValidationErrorSource{Pointer: "/photoUrls"}},
 		},
 		{
 			name: "error - unsupported content-type on POST",
@@ -1,8 +1,14 @@
 package openapi3filter
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"io/ioutil"
+	"log"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/routers"
@@ -15,12 +21,51 @@ func NoopAuthenticationFunc(context.Context, *AuthenticationInput) error { retur
 
 var _ AuthenticationFunc = NoopAuthenticationFunc
 
+// ResponseValidationMode tells a ValidationHandler what to do with responses.
+type ResponseValidationMode int
+
+const (
+	// ResponseValidationOff sends responses without validating them.
+	ResponseValidationOff ResponseValidationMode = iota
+	// ResponseValidationReport validates responses and reports errors
+	// to ResponseErrorReporter, responses are sent unchanged.
+	ResponseValidationReport
+	// ResponseValidationReplace validates responses, reports errors
+	// to ResponseErrorReporter and replaces invalid responses with the ErrorEncoder's
+	// encoding of the error: a 500 with DefaultErrorEncoder.
+	ResponseValidationReplace
+)
+
 type ValidationHandler struct {
 	Handler            http.Handler
 	AuthenticationFunc AuthenticationFunc
 	File               string
 	ErrorEncoder       ErrorEncoder
-	router             routers.Router
+
+	// RequestOptions are the Options used to validate requests,
+	// with the AuthenticationFunc of the handler.
+	// Requests reach the handler with their DecodedRequest (see DecodedRequestFromContext),
+	// so that with FillDefaults it sees the default values of absent parameters and properties.
+	RequestOptions *Options
+	// ResponseValidation enables validating responses written by the handler.
+	// Responses are then buffered entirely before being sent, except those
+	// of a content type with a registered StreamDecoder: these are sent as they
+	// are written and validated concurrently, so errors are only reported.
+	ResponseValidation ResponseValidationMode
+	// ResponseOptions are the Options used to validate responses.
+	ResponseOptions *Options
+	// ResponseErrorReporter is called with errors found validating responses.
+	// Errors are logged when it is nil.
+	ResponseErrorReporter func(*http.Request, error)
+
+	// HandleOptions answers OPTIONS requests to paths without an OPTIONS operation
+	// with a 204 No Content listing the methods of the path in an Allow header.
+	HandleOptions bool
+	// HandleHead serves HEAD requests to paths without a HEAD operation
+	// as GET requests whose response body is discarded.
+	HandleHead bool
+
+	router routers.Router
 }
 
 func (h *ValidationHandler) Load() error {
@@ -51,42 +96,200 @@ func (h *ValidationHandler) Load() error {
 }
 
 func (h *ValidationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if handled := h.before(w, r); handled {
+	w, input, handled := h.before(w, r)
+	if handled {
 		return
 	}
-	// TODO: validateResponse
-	h.Handler.ServeHTTP(w, r)
+	h.serve(h.Handler, w, input)
 }
 
-// Middleware implements gorilla/mux MiddlewareFunc
+// Middleware implements gorilla/mux MiddlewareFunc.
+// The request passed to next carries a DecodedRequest, see DecodedRequestFromContext.
 func (h *ValidationHandler) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if handled := h.before(w, r); handled {
+		w, input, handled := h.before(w, r)
+		if handled {
 			return
 		}
-		// TODO: validateResponse
-		next.ServeHTTP(w, r)
+		h.serve(next, w, input)
 	})
 }
 
-func (h *ValidationHandler) before(w http.ResponseWriter, r *http.Request) (handled bool) {
-	if err := h.validateRequest(r); err != nil {
+// before validates r, returning where to write its response,
+// unless it handled r itself: OPTIONS requests and errors.
+func (h *ValidationHandler) before(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *RequestValidationInput, bool) {
+	input, err := h.decodeRequest(r)
+	if e, ok := err.(*routers.RouteError); ok && len(e.AllowedMethods) != 0 {
+		allowed := h.allowedMethods(e.AllowedMethods)
+		switch {
+		case h.HandleOptions && r.Method == http.MethodOptions:
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return w, nil, true
+		case h.HandleHead && r.Method == http.MethodHead && containsMethod(e.AllowedMethods, http.MethodGet):
+			get := r.Clone(r.Context())
+			get.Method = http.MethodGet
+			if input, err = h.decodeRequest(get); err == nil {
+				return headResponseWriter{w}, input, false
+			}
+		default:
+			err = &routers.RouteError{Reason: e.Reason, Path: e.Path, AllowedMethods: allowed}
+		}
+	}
+	if err != nil {
 		h.ErrorEncoder(r.Context(), err, w)
-		return true
+		return w, nil, true
+	}
+	return w, input, false
+}
+
+// allowedMethods adds the methods handled by h to those of a path.
+func (h *ValidationHandler) allowedMethods(methods []string) []string {
+	allowed := append([]string(nil), methods...)
+	if h.HandleHead && containsMethod(methods, http.MethodGet) && !containsMethod(methods, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	if h.HandleOptions && !containsMethod(methods, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
 	}
 	return false
 }
 
+// headResponseWriter discards the body of responses to HEAD requests.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(data []byte) (int, error) { return len(data), nil }
+
+func (w headResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// serve calls next and, depending on ResponseValidation, validates what it wrote.
+func (h *ValidationHandler) serve(next http.Handler, w http.ResponseWriter, input *RequestValidationInput) {
+	r := input.Request
+	if h.ResponseValidation == ResponseValidationOff {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	reporter := h.ResponseErrorReporter
+	if reporter == nil {
+		reporter = logResponseError
+	}
+
+	rec := newResponseRecorder()
+	rec.startStream = func(status int) io.WriteCloser {
+		return h.validateStream(w, input, status, rec.header, reporter)
+	}
+	next.ServeHTTP(rec, r)
+	if rec.stream != nil {
+		rec.stream.Close()
+		return
+	}
+
+	err := ValidateResponse(r.Context(), &ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 rec.statusCode(),
+		Header:                 rec.header,
+		Body:                   ioutil.NopCloser(bytes.NewReader(rec.body.Bytes())),
+		Options:                h.ResponseOptions,
+	})
+	if err != nil {
+		reporter(r, err)
+		if h.ResponseValidation == ResponseValidationReplace {
+			h.ErrorEncoder(r.Context(), err, w)
+			return
+		}
+	}
+	rec.writeTo(w)
+}
+
+// validateStream sends the status and header of a streamed response to w and
+// returns where to write its body: to w, and to a concurrent validation
+// of its records that reports errors once the returned writer is closed.
+func (h *ValidationHandler) validateStream(w http.ResponseWriter, input *RequestValidationInput,
+	status int, header http.Header, reporter func(*http.Request, error)) io.WriteCloser {
+	copyHeader(w.Header(), header)
+	w.WriteHeader(status)
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := ValidateResponse(input.Request.Context(), &ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 status,
+			Header:                 header,
+			Body:                   pr,
+			Options:                h.ResponseOptions,
+		})
+		// Let the handler write what validation did not read
+		io.Copy(ioutil.Discard, pr)
+		if err != nil {
+			reporter(input.Request, err)
+		}
+	}()
+	return &streamWriter{w: w, pipe: pw, done: done}
+}
+
+// streamWriter writes a streamed response body to both the client and its validation.
+type streamWriter struct {
+	w    http.ResponseWriter
+	pipe *io.PipeWriter
+	done chan struct{}
+}
+
+func (s *streamWriter) Write(data []byte) (int, error) {
+	n, err := s.w.Write(data)
+	s.pipe.Write(data[:n])
+	return n, err
+}
+
+func (s *streamWriter) Flush() {
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *streamWriter) Close() error {
+	s.pipe.Close()
+	<-s.done
+	return nil
+}
+
 func (h *ValidationHandler) validateRequest(r *http.Request) error {
+	_, err := h.decodeRequest(r)
+	return err
+}
+
+// decodeRequest validates r and returns the validated input,
+// whose request carries a DecodedRequest in its context.
+func (h *ValidationHandler) decodeRequest(r *http.Request) (*RequestValidationInput, error) {
 	// Find route
 	route, pathParams, err := h.router.FindRoute(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	options := &Options{
-		AuthenticationFunc: h.AuthenticationFunc,
+	options := &Options{}
+	if h.RequestOptions != nil {
+		*options = *h.RequestOptions
 	}
+	options.AuthenticationFunc = h.AuthenticationFunc
 
 	// Validate request
 	requestValidationInput := &RequestValidationInput{
@@ -96,8 +299,82 @@ func (h *ValidationHandler) validateRequest(r *http.Request) error {
 		Options:    options,
 	}
 	if err = ValidateRequest(r.Context(), requestValidationInput); err != nil {
-		return err
+		return nil, err
+	}
+	if decoded := requestValidationInput.DecodedRequest(); decoded != nil {
+		requestValidationInput.Request = withDecodedRequest(r, decoded)
 	}
 
-	return nil
+	return requestValidationInput, nil
+}
+
+func logResponseError(r *http.Request, err error) {
+	log.Printf("openapi3filter: invalid response to %s %s: %v", r.Method, r.URL.Path, err)
+}
+
+// responseRecorder buffers what a handler writes so the response
+// can be validated before being sent.
+// Streamed responses are not buffered but written to the writer returned by startStream.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+
+	startStream func(status int) io.WriteCloser
+	stream      io.WriteCloser
+}
+
+var _ http.ResponseWriter = (*responseRecorder)(nil)
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header)}
+}
+
+func (rec *responseRecorder) Header() http.Header { return rec.header }
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status != 0 {
+		return
+	}
+	rec.status = status
+	if rec.startStream != nil && streamDecoders[parseMediaType(rec.header.Get(headerCT))] != nil {
+		rec.stream = rec.startStream(status)
+	}
+}
+
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	if rec.stream != nil {
+		return rec.stream.Write(data)
+	}
+	return rec.body.Write(data)
+}
+
+// Flush implements http.Flusher, flushing streamed responses to the client.
+func (rec *responseRecorder) Flush() {
+	if rec.stream == nil {
+		return
+	}
+	if f, ok := rec.stream.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (rec *responseRecorder) statusCode() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+func (rec *responseRecorder) writeTo(w http.ResponseWriter) {
+	copyHeader(w.Header(), rec.header)
+	w.WriteHeader(rec.statusCode())
+	w.Write(rec.body.Bytes())
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, values := range src {
+		dst[k] = values
+	}
 }
@@ -0,0 +1,239 @@
+package openapi3filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationHandlerResponseValidation(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	handlerWriting := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Served-By", "test")
+			w.Write([]byte(body))
+		})
+	}
+
+	tests := []struct {
+		name     string
+		mode     ResponseValidationMode
+		body     string
+		status   int
+		reported bool
+	}{
+		{name: "off, invalid", mode: ResponseValidationOff, body: `{}`, status: http.StatusOK},
+		{name: "report, valid", mode: ResponseValidationReport, body: `{"name":"Kitty"}`, status: http.StatusOK},
+		{name: "report, invalid", mode: ResponseValidationReport, body: `{}`, status: http.StatusOK, reported: true},
+		{name: "replace, valid", mode: ResponseValidationReplace, body: `{"name":"Kitty"}`, status: http.StatusOK},
+		{name: "replace, invalid", mode: ResponseValidationReplace, body: `{}`, status: http.StatusInternalServerError, reported: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported error
+			h := &ValidationHandler{
+				Handler:            handlerWriting(tt.body),
+				AuthenticationFunc: NoopAuthenticationFunc,
+				ErrorEncoder:       DefaultErrorEncoder,
+				ResponseValidation: tt.mode,
+				ResponseErrorReporter: func(r *http.Request, err error) {
+					reported = err
+				},
+				router: router,
+			}
+
+			r, err := http.NewRequest(http.MethodGet, "/pets", nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			require.Equal(t, tt.status, w.Code)
+			if !tt.reported {
+				require.NoError(t, reported)
+				require.Equal(t, tt.body, w.Body.String())
+				require.Equal(t, "test", w.Header().Get("X-Served-By"))
+				return
+			}
+			require.Error(t, reported)
+			require.IsType(t, &ResponseError{}, reported)
+			if tt.status == http.StatusOK {
+				require.Equal(t, tt.body, w.Body.String())
+			} else {
+				require.Contains(t, w.Body.String(), "doesn't match the schema")
+			}
+		})
+	}
+}
+
+func TestValidationHandlerRequestOptions(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets:
+    get:
+      parameters:
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          default: 20
+      responses:
+        '200':
+          description: OK
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	var decoded *DecodedRequest
+	var query string
+	h := &ValidationHandler{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			decoded = DecodedRequestFromContext(r.Context())
+			query = r.URL.RawQuery
+		}),
+		AuthenticationFunc: NoopAuthenticationFunc,
+		ErrorEncoder:       DefaultErrorEncoder,
+		RequestOptions:     &Options{FillDefaults: true},
+		router:             router,
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "/pets", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, decoded)
+	require.Equal(t, map[string]interface{}{"limit": float64(20)}, decoded.QueryParams)
+	require.Equal(t, "limit=20", query)
+	require.Nil(t, h.RequestOptions.AuthenticationFunc)
+}
+
+func TestValidationHandlerAllowedMethods(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /pets/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+    get:
+      responses:
+        '200':
+          description: OK
+    delete:
+      responses:
+        '204':
+          description: No Content
+`
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	var served []string
+	newHandler := func(handleOptions, handleHead bool) *ValidationHandler {
+		return &ValidationHandler{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served = append(served, r.Method)
+				w.Header().Set("X-Served-By", "test")
+				w.Write([]byte("body"))
+			}),
+			AuthenticationFunc: NoopAuthenticationFunc,
+			ErrorEncoder:       (&ValidationErrorEncoder{Encoder: DefaultErrorEncoder}).Encode,
+			HandleOptions:      handleOptions,
+			HandleHead:         handleHead,
+			router:             router,
+		}
+	}
+	serve := func(h *ValidationHandler, method, path string) *httptest.ResponseRecorder {
+		served = nil
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	h := newHandler(false, false)
+	w := serve(h, http.MethodPut, "/pets/1")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, "DELETE, GET", w.Header().Get("Allow"))
+	w = serve(h, http.MethodOptions, "/pets/1")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	w = serve(h, http.MethodHead, "/pets/1")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Empty(t, served)
+
+	h = newHandler(true, true)
+	w = serve(h, http.MethodPut, "/pets/1")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, "DELETE, GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+
+	w = serve(h, http.MethodOptions, "/pets/1")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, "DELETE, GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+	require.Empty(t, served)
+
+	w = serve(h, http.MethodHead, "/pets/1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "test", w.Header().Get("X-Served-By"))
+	require.Empty(t, w.Body.String())
+	require.Equal(t, []string{http.MethodGet}, served)
+
+	// HEAD requests are validated as GET ones
+	w = serve(h, http.MethodHead, "/pets/kitty")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), `resource not found with "id" value: kitty`)
+	require.Empty(t, served)
+}
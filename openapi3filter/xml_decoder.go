@@ -0,0 +1,327 @@
+package openapi3filter
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// xmlElement is an element of a parsed XML document.
+type xmlElement struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlElement
+	text     strings.Builder
+}
+
+// parseXML returns the root element of the XML document read from body.
+func parseXML(body io.Reader) (*xmlElement, error) {
+	var (
+		root  *xmlElement
+		stack []*xmlElement
+	)
+	dec := xml.NewDecoder(body)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			el := &xmlElement{name: tok.Name, attrs: tok.Attr}
+			if n := len(stack); n > 0 {
+				stack[n-1].children = append(stack[n-1].children, el)
+			} else if root == nil {
+				root = el
+			} else {
+				return nil, errors.New("XML document has more than one root element")
+			}
+			stack = append(stack, el)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if n := len(stack); n > 0 {
+				stack[n-1].text.Write(tok)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("XML document has no root element")
+	}
+	return root, nil
+}
+
+// xmlBodyDecoder decodes an XML document to the value model of JSON,
+// mapping elements and attributes to the properties of the body's schema
+// according to their XML objects.
+func xmlBodyDecoder(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encFn EncodingFn) (interface{}, error) {
+	root, err := parseXML(body)
+	if err != nil {
+		return nil, &ParseError{Kind: KindInvalidFormat, Cause: err}
+	}
+	if hints := xmlHints(schema); !xmlNameMatches(root.name, hints.Name, hints.Namespace) {
+		return nil, &ParseError{
+			Kind:   KindInvalidFormat,
+			Value:  root.name.Local,
+			Reason: fmt.Sprintf("root element must be %q", hints.Name),
+		}
+	}
+	return xmlValue(root, schema)
+}
+
+// xmlHints returns the XML object of a schema, or an empty one.
+func xmlHints(schema *openapi3.SchemaRef) *openapi3.XML {
+	if schema != nil && schema.Value != nil && schema.Value.XML != nil {
+		return schema.Value.XML
+	}
+	return &openapi3.XML{}
+}
+
+// xmlNameMatches reports whether name has the given local name and namespace,
+// any of which may be empty to match all names.
+func xmlNameMatches(name xml.Name, local, namespace string) bool {
+	return (local == "" || name.Local == local) && (namespace == "" || name.Space == namespace)
+}
+
+// isXMLNamespaceDeclaration reports whether attr declares a namespace rather than holds data.
+func isXMLNamespaceDeclaration(attr xml.Attr) bool {
+	return attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns")
+}
+
+// xmlSchemaType returns the type the value of an element is decoded to.
+func xmlSchemaType(schema *openapi3.Schema) string {
+	types := schema.Types
+	if schema.Type != "" {
+		types = []string{schema.Type}
+	}
+	for _, typ := range types {
+		if typ != "null" {
+			return typ
+		}
+	}
+	switch {
+	case len(schema.Properties) != 0 || schema.AdditionalProperties != nil:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// xmlValue decodes element el following schema.
+func xmlValue(el *xmlElement, schema *openapi3.SchemaRef) (interface{}, error) {
+	if schema == nil || schema.Value == nil {
+		return xmlUntypedValue(el), nil
+	}
+	switch typ := xmlSchemaType(schema.Value); typ {
+	case "object":
+		return xmlObject(el, schema.Value)
+	case "array":
+		// Elements holding arrays only contain their items
+		itemName := xmlHints(schema.Value.Items).Name
+		return xmlArray(el.children, itemName, "", schema.Value.Items)
+	case "":
+		return xmlUntypedValue(el), nil
+	default:
+		return xmlPrimitive(el.text.String(), typ)
+	}
+}
+
+// xmlPrimitive parses the text of an element or attribute to a primitive type.
+func xmlPrimitive(raw, typ string) (interface{}, error) {
+	if typ == "string" {
+		return raw, nil
+	}
+	return parsePrimitive(strings.TrimSpace(raw), &openapi3.SchemaRef{Value: &openapi3.Schema{Type: typ}})
+}
+
+// xmlArray decodes the elements among children with the given name and namespace to an array.
+func xmlArray(children []*xmlElement, name, namespace string, items *openapi3.SchemaRef) ([]interface{}, error) {
+	list := make([]interface{}, 0, len(children))
+	for _, child := range children {
+		if !xmlNameMatches(child.name, name, namespace) {
+			continue
+		}
+		item, err := xmlValue(child, items)
+		if err != nil {
+			if v, ok := err.(*ParseError); ok {
+				return nil, &ParseError{path: []interface{}{len(list)}, Cause: v}
+			}
+			return nil, fmt.Errorf("item %d: %s", len(list), err)
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// xmlObject decodes el to an object. Declared properties are looked up by their XML name,
+// as attributes or child elements, with arrays possibly wrapped in an element of their own.
+// Undeclared attributes and child elements are kept so the schema can reject them.
+func xmlObject(el *xmlElement, schema *openapi3.Schema) (map[string]interface{}, error) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obj := make(map[string]interface{})
+	claimedAttrs := make(map[int]struct{})
+	claimedChildren := make(map[*xmlElement]struct{})
+	for _, name := range names {
+		prop := schema.Properties[name]
+		hints := xmlHints(prop)
+		xmlName := name
+		if hints.Name != "" {
+			xmlName = hints.Name
+		}
+
+		var (
+			value interface{}
+			found bool
+			err   error
+		)
+		switch {
+		case hints.Attribute:
+			for i, attr := range el.attrs {
+				if xmlNameMatches(attr.Name, xmlName, hints.Namespace) {
+					claimedAttrs[i] = struct{}{}
+					typ := "string"
+					if prop.Value != nil {
+						typ = xmlSchemaType(prop.Value)
+					}
+					value, err = xmlPrimitive(attr.Value, typ)
+					found = true
+					break
+				}
+			}
+
+		case prop.Value != nil && xmlSchemaType(prop.Value) == "array":
+			// Items are named after the property unless named themselves
+			itemHints := xmlHints(prop.Value.Items)
+			itemName := name
+			if itemHints.Name != "" {
+				itemName = itemHints.Name
+			}
+			children := el.children
+			if hints.Wrapped {
+				wrapper := findXMLChild(el, xmlName, hints.Namespace, claimedChildren)
+				if wrapper == nil {
+					continue
+				}
+				claimedChildren[wrapper] = struct{}{}
+				children = wrapper.children
+			} else {
+				for _, child := range children {
+					if xmlNameMatches(child.name, itemName, itemHints.Namespace) {
+						claimedChildren[child] = struct{}{}
+						found = true
+					}
+				}
+				if !found {
+					continue
+				}
+			}
+			value, err = xmlArray(children, itemName, itemHints.Namespace, prop.Value.Items)
+			found = true
+
+		default:
+			if child := findXMLChild(el, xmlName, hints.Namespace, claimedChildren); child != nil {
+				claimedChildren[child] = struct{}{}
+				value, err = xmlValue(child, prop)
+				found = true
+			}
+		}
+		if err != nil {
+			if v, ok := err.(*ParseError); ok {
+				return nil, &ParseError{path: []interface{}{name}, Cause: v}
+			}
+			return nil, fmt.Errorf("property %q: %s", name, err)
+		}
+		if found {
+			obj[name] = value
+		}
+	}
+
+	for i, attr := range el.attrs {
+		if _, ok := claimedAttrs[i]; ok || isXMLNamespaceDeclaration(attr) {
+			continue
+		}
+		if _, ok := obj[attr.Name.Local]; !ok {
+			obj[attr.Name.Local] = attr.Value
+		}
+	}
+	values := make(map[string][]interface{})
+	for _, child := range el.children {
+		if _, ok := claimedChildren[child]; ok {
+			continue
+		}
+		name := child.name.Local
+		value, err := xmlValue(child, schema.AdditionalProperties)
+		if err != nil {
+			if v, ok := err.(*ParseError); ok {
+				return nil, &ParseError{path: []interface{}{name}, Cause: v}
+			}
+			return nil, fmt.Errorf("property %q: %s", name, err)
+		}
+		values[name] = append(values[name], value)
+	}
+	setXMLValues(obj, values)
+	return obj, nil
+}
+
+// findXMLChild returns the first child of el with the given name and namespace not yet claimed.
+func findXMLChild(el *xmlElement, name, namespace string, claimed map[*xmlElement]struct{}) *xmlElement {
+	for _, child := range el.children {
+		if _, ok := claimed[child]; ok {
+			continue
+		}
+		if xmlNameMatches(child.name, name, namespace) {
+			return child
+		}
+	}
+	return nil
+}
+
+// xmlUntypedValue decodes an element no schema describes: to its text if it has
+// no attributes nor children, otherwise to an object with repeated children as arrays.
+func xmlUntypedValue(el *xmlElement) interface{} {
+	obj := make(map[string]interface{})
+	for _, attr := range el.attrs {
+		if !isXMLNamespaceDeclaration(attr) {
+			obj[attr.Name.Local] = attr.Value
+		}
+	}
+	values := make(map[string][]interface{})
+	for _, child := range el.children {
+		values[child.name.Local] = append(values[child.name.Local], xmlUntypedValue(child))
+	}
+	setXMLValues(obj, values)
+	if len(obj) == 0 {
+		return el.text.String()
+	}
+	return obj
+}
+
+// setXMLValues sets the properties of obj not set yet to the values of the child elements
+// with their names, as arrays if they repeat.
+func setXMLValues(obj map[string]interface{}, values map[string][]interface{}) {
+	for name, vs := range values {
+		if _, ok := obj[name]; ok {
+			continue
+		}
+		if len(vs) == 1 {
+			obj[name] = vs[0]
+		} else {
+			obj[name] = vs
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package openapi3filter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+const xmlSpec = `
+openapi: 3.0.0
+info:
+  title: 'Validator'
+  version: 0.0.1
+paths:
+  /orders:
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/Order'
+      responses:
+        '201':
+          description: Created
+          content:
+            application/vnd.acme.order+xml:
+              schema:
+                $ref: '#/components/schemas/Order'
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id, lines]
+      additionalProperties: false
+      properties:
+        id:
+          type: integer
+          xml:
+            attribute: true
+        customer:
+          type: string
+          xml:
+            name: Customer
+            namespace: 'https://example.com/crm'
+        paid:
+          type: boolean
+        lines:
+          type: array
+          xml:
+            name: Lines
+            wrapped: true
+          items:
+            $ref: '#/components/schemas/Line'
+        notes:
+          type: array
+          items:
+            type: string
+            xml:
+              name: note
+      xml:
+        name: order
+    Line:
+      type: object
+      properties:
+        sku:
+          type: string
+        quantity:
+          type: integer
+          minimum: 1
+      xml:
+        name: line
+`
+
+func TestXMLBodyDecoder(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(xmlSpec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	schema := doc.Components.Schemas["Order"]
+
+	decode := func(body string) (interface{}, error) {
+		h := make(http.Header)
+		h.Set(headerCT, "application/xml")
+		return decodeBody(strings.NewReader(body), h, schema, nil)
+	}
+
+	got, err := decode(`<?xml version="1.0"?>
+<order id="42" xmlns:crm="https://example.com/crm">
+  <crm:Customer>ACME</crm:Customer>
+  <paid> true </paid>
+  <Lines>
+    <line><sku>A-1</sku><quantity>2</quantity></line>
+    <line><sku>B-2</sku><quantity>1</quantity></line>
+  </Lines>
+  <note>fragile</note>
+  <note>gift</note>
+</order>`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id":       42.0,
+		"customer": "ACME",
+		"paid":     true,
+		"lines": []interface{}{
+			map[string]interface{}{"sku": "A-1", "quantity": 2.0},
+			map[string]interface{}{"sku": "B-2", "quantity": 1.0},
+		},
+		"notes": []interface{}{"fragile", "gift"},
+	}, got)
+	require.NoError(t, schema.Value.VisitJSON(got))
+
+	// Undeclared content, such as elements outside of their namespace, is kept for the schema to reject
+	got, err = decode(`<order id="1"><Lines/><Customer>ACME</Customer></order>`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id":       1.0,
+		"lines":    []interface{}{},
+		"Customer": "ACME",
+	}, got)
+	err = schema.Value.VisitJSON(got)
+	require.Error(t, err)
+	require.Equal(t, `property "Customer" is unsupported`, err.(*openapi3.SchemaError).Reason)
+
+	_, err = decode(`<order id="1"><Lines><line><quantity>many</quantity></line></Lines></order>`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `value many: an invalid integer`)
+
+	_, err = decode(`<invoice id="1"/>`)
+	require.EqualError(t, err, `value invoice: root element must be "order"`)
+
+	_, err = decode(`<order id="1">`)
+	require.Error(t, err)
+	require.Equal(t, KindInvalidFormat, err.(*ParseError).Kind)
+}
+
+func TestValidateXMLRequestAndResponse(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(xmlSpec))
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+
+	const order = `<order id="7"><Lines><line><sku>A-1</sku><quantity>0</quantity></line></Lines></order>`
+	req, err := http.NewRequest(http.MethodPost, "/orders", strings.NewReader(order))
+	require.NoError(t, err)
+	req.Header.Set(headerCT, "application/xml; charset=utf-8")
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+	requestValidationInput := &RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+	}
+	err = ValidateRequest(context.Background(), requestValidationInput)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Error at "/lines/0/quantity": number must be at least 1`)
+
+	header := make(http.Header)
+	header.Set(headerCT, "application/vnd.acme.order+xml")
+	responseValidationInput := &ResponseValidationInput{
+		RequestValidationInput: requestValidationInput,
+		Status:                 http.StatusCreated,
+		Header:                 header,
+	}
+	responseValidationInput.SetBodyBytes([]byte(strings.Replace(order, "<quantity>0", "<quantity>3", 1)))
+	err = ValidateResponse(context.Background(), responseValidationInput)
+	require.NoError(t, err)
+
+	responseValidationInput.Body = ioutil.NopCloser(strings.NewReader(`<order id="7"/>`))
+	err = ValidateResponse(context.Background(), responseValidationInput)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `property "lines" is missing`)
+}
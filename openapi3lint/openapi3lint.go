@@ -0,0 +1,328 @@
+// Package openapi3lint checks OpenAPIv3 documents against style rules,
+// beyond the structural checks of openapi3.T.Validate.
+//
+// Rules are kept in a Registry: DefaultRegistry holds the built-in ones
+// and more can be registered. Each rule reports findings at a severity
+// that can be changed or turned off when linting.
+// Findings on an element can be silenced with an x-lint-ignore extension
+// listing the IDs of the rules to ignore on it and everything it contains:
+//
+//	paths:
+//	  /legacy_items:
+//	    x-lint-ignore: [path-kebab-case]
+//
+// Documents are expected to be loaded with their references resolved.
+package openapi3lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Severity tells how serious a finding is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	// SeverityOff disables a rule.
+	SeverityOff Severity = "off"
+)
+
+// IgnoreExtension is the extension listing the rules to ignore on an element.
+const IgnoreExtension = "x-lint-ignore"
+
+// Finding describes one element of a document breaking a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Pointer is the JSON pointer of the element in the document
+	// (e.g. "/paths/~1pets/get/operationId").
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+	// Location is where the element was defined,
+	// known when the document was loaded with Loader.IncludeSourceLocations.
+	Location *openapi3.SourceLocation `json:"location,omitempty"`
+}
+
+func (finding *Finding) String() string {
+	s := fmt.Sprintf("%s: %s [%s %s]", finding.Pointer, finding.Message, finding.Severity, finding.Rule)
+	if finding.Location != nil {
+		s = finding.Location.String() + ": " + s
+	}
+	return s
+}
+
+// Report lists the findings of a run, ordered by pointer.
+// It marshals to JSON, see MarshalSARIF for the SARIF format.
+type Report struct {
+	Findings []*Finding `json:"findings"`
+
+	// rules that were run, for their descriptions
+	rules []*Rule
+}
+
+// Reporter is given to rules to report elements breaking them,
+// by their JSON pointer in the document.
+type Reporter func(pointer string, message string)
+
+// Rule checks a document.
+type Rule struct {
+	// ID names the rule in findings, options and x-lint-ignore extensions.
+	ID          string
+	Description string
+	// Severity of the findings when not set by the WithSeverity option.
+	Severity Severity
+	Check    func(doc *openapi3.T, report Reporter)
+}
+
+// Registry holds the rules Lint runs.
+type Registry struct {
+	rules map[string]*Rule
+}
+
+// NewRegistry returns a Registry without rules.
+func NewRegistry() *Registry {
+	return &Registry{rules: make(map[string]*Rule)}
+}
+
+// DefaultRegistry returns a new Registry holding the built-in rules.
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	if err := registry.Register(builtinRules()...); err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Register adds rules to the registry.
+func (registry *Registry) Register(rules ...*Rule) error {
+	for _, rule := range rules {
+		if rule.ID == "" || rule.Check == nil {
+			return fmt.Errorf("rule %q must have an ID and a Check function", rule.ID)
+		}
+		if _, ok := registry.rules[rule.ID]; ok {
+			return fmt.Errorf("rule %q is already registered", rule.ID)
+		}
+		registry.rules[rule.ID] = rule
+	}
+	return nil
+}
+
+// Rule returns the registered rule with the given ID, or nil.
+func (registry *Registry) Rule(id string) *Rule {
+	return registry.rules[id]
+}
+
+// Rules returns the registered rules, ordered by ID.
+func (registry *Registry) Rules() []*Rule {
+	rules := make([]*Rule, 0, len(registry.rules))
+	for _, rule := range registry.rules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules
+}
+
+// Option describes options a user has when linting a document.
+type Option func(*settings)
+
+type settings struct {
+	registry   *Registry
+	severities map[string]Severity
+}
+
+// WithRegistry makes Lint run the rules of registry instead of the built-in ones.
+func WithRegistry(registry *Registry) Option {
+	return func(s *settings) { s.registry = registry }
+}
+
+// WithSeverity sets the severity of the findings of a rule.
+// SeverityOff disables the rule.
+func WithSeverity(id string, severity Severity) Option {
+	return func(s *settings) { s.severities[id] = severity }
+}
+
+// DisableRules turns rules off.
+func DisableRules(ids ...string) Option {
+	return func(s *settings) {
+		for _, id := range ids {
+			s.severities[id] = SeverityOff
+		}
+	}
+}
+
+// Lint runs the rules on doc.
+func Lint(doc *openapi3.T, opts ...Option) *Report {
+	s := &settings{severities: make(map[string]Severity)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.registry == nil {
+		s.registry = DefaultRegistry()
+	}
+
+	ignored := collectIgnored(doc)
+
+	report := &Report{Findings: []*Finding{}}
+	for _, rule := range s.registry.Rules() {
+		severity := rule.Severity
+		if sev, ok := s.severities[rule.ID]; ok {
+			severity = sev
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		report.rules = append(report.rules, rule)
+		rule.Check(doc, func(pointer string, message string) {
+			if isIgnored(ignored, rule.ID, pointer) {
+				return
+			}
+			finding := &Finding{
+				Rule:     rule.ID,
+				Severity: severity,
+				Pointer:  pointer,
+				Message:  message,
+			}
+			if location, ok := doc.SourceLocationAt(pointer); ok {
+				finding.Location = &location
+			}
+			report.Findings = append(report.Findings, finding)
+		})
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Pointer < report.Findings[j].Pointer
+	})
+	return report
+}
+
+// isIgnored tells whether the element at pointer, or one containing it,
+// ignores the rule.
+func isIgnored(ignored map[string][]string, rule string, pointer string) bool {
+	for p, rules := range ignored {
+		if p != "" && p != pointer && !strings.HasPrefix(pointer, p+"/") {
+			continue
+		}
+		for _, id := range rules {
+			if id == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectIgnored maps the JSON pointers of the elements of doc
+// to the rules their x-lint-ignore extension lists.
+// Referenced values are handled where they are defined.
+func collectIgnored(doc *openapi3.T) map[string][]string {
+	ignored := make(map[string][]string)
+	add := func(pointer string, props openapi3.ExtensionProps) {
+		if rules := ignoredRules(props); len(rules) != 0 {
+			ignored[pointer] = rules
+		}
+	}
+
+	add("", doc.ExtensionProps)
+	if doc.Info != nil {
+		add("/info", doc.Info.ExtensionProps)
+	}
+	for i, server := range doc.Servers {
+		if server != nil {
+			add(openapi3.JoinJSONPointer("/servers", strconv.Itoa(i)), server.ExtensionProps)
+		}
+	}
+	for i, tag := range doc.Tags {
+		if tag != nil {
+			add(openapi3.JoinJSONPointer("/tags", strconv.Itoa(i)), tag.ExtensionProps)
+		}
+	}
+	add("/components", doc.Components.ExtensionProps)
+
+	doc.Walk(&openapi3.Visitor{
+		OnPathItem: func(pointer string, pathItem *openapi3.PathItem) openapi3.WalkAction {
+			add(pointer, pathItem.ExtensionProps)
+			return openapi3.WalkContinue
+		},
+		OnOperation: func(pointer string, method string, operation *openapi3.Operation) openapi3.WalkAction {
+			add(pointer, operation.ExtensionProps)
+			return openapi3.WalkContinue
+		},
+		OnParameter: func(pointer string, ref *openapi3.ParameterRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnRequestBody: func(pointer string, ref *openapi3.RequestBodyRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnResponse: func(pointer string, ref *openapi3.ResponseRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnHeader: func(pointer string, ref *openapi3.HeaderRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnMediaType: func(pointer string, mediaType *openapi3.MediaType) openapi3.WalkAction {
+			add(pointer, mediaType.ExtensionProps)
+			return openapi3.WalkContinue
+		},
+		OnSchema: func(pointer string, ref *openapi3.SchemaRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnExample: func(pointer string, ref *openapi3.ExampleRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnLink: func(pointer string, ref *openapi3.LinkRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+		OnSecurityScheme: func(pointer string, ref *openapi3.SecuritySchemeRef) openapi3.WalkAction {
+			if ref.Ref == "" && ref.Value != nil {
+				add(pointer, ref.Value.ExtensionProps)
+			}
+			return openapi3.WalkContinue
+		},
+	})
+	return ignored
+}
+
+// ignoredRules reads an x-lint-ignore extension: a rule ID or a list of them.
+func ignoredRules(props openapi3.ExtensionProps) []string {
+	raw, ok := props.Extensions[IgnoreExtension].(json.RawMessage)
+	if !ok {
+		return nil
+	}
+	var rules []string
+	if err := json.Unmarshal(raw, &rules); err == nil {
+		return rules
+	}
+	var rule string
+	if err := json.Unmarshal(raw, &rule); err == nil && rule != "" {
+		return []string{rule}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package openapi3lint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      description: Lists pets.
+      tags: [pets]
+      x-lint-ignore: [operation-id-unique]
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+        '400':
+          $ref: '#/components/responses/Error'
+  /pets/{id}:
+    get:
+      operationId: list_pets
+      responses:
+        '200':
+          description: A pet
+          content:
+            application/json:
+              schema:
+                type: object
+  /petOwners/{ownerId}:
+    x-lint-ignore: path-kebab-case
+    get:
+      operationId: listPets
+      description: Lists owners.
+      tags: [owners]
+      parameters:
+      - name: ownerId
+        in: path
+        required: true
+        schema:
+          type: string
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+      responses:
+        '404':
+          description: Not found
+components:
+  schemas:
+    Pet:
+      type: string
+  responses:
+    Error:
+      description: Error
+      content:
+        application/json:
+          schema:
+            type: object
+`
+
+func loadSpec(t *testing.T) *openapi3.T {
+	loader := openapi3.NewLoader()
+	loader.IncludeSourceLocations = true
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	return doc
+}
+
+func findings(report *Report) []string {
+	var lines []string
+	for _, finding := range report.Findings {
+		lines = append(lines, string(finding.Severity)+" "+finding.Rule+" "+finding.Pointer)
+	}
+	return lines
+}
+
+func TestLint(t *testing.T) {
+	doc := loadSpec(t)
+
+	report := Lint(doc)
+	require.Equal(t, []string{
+		"warning no-inline-response-schema /components/responses/Error/content/application~1json/schema",
+		"error path-params-declared /paths/~1petOwners~1{ownerId}/get/parameters/1",
+		"warning operation-description /paths/~1pets~1{id}/get",
+		"warning operation-tags /paths/~1pets~1{id}/get",
+		"error path-params-declared /paths/~1pets~1{id}/get",
+		"warning operation-id-camel-case /paths/~1pets~1{id}/get/operationId",
+		"warning operation-4xx-response /paths/~1pets~1{id}/get/responses",
+		"warning no-inline-response-schema /paths/~1pets~1{id}/get/responses/200/content/application~1json/schema",
+	}, findings(report))
+
+	finding := report.Findings[2]
+	require.Equal(t, "operation has no description", finding.Message)
+	require.Equal(t, &openapi3.SourceLocation{Line: 25, Column: 5}, finding.Location)
+	require.Equal(t, "25:5: /paths/~1pets~1{id}/get: operation has no description [warning operation-description]", finding.String())
+	require.Equal(t, `path parameter "id" is not part of the path`, report.Findings[1].Message)
+	require.Equal(t, `path parameter "id" is not declared`, report.Findings[4].Message)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded struct {
+		Findings []*Finding `json:"findings"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, report.Findings, decoded.Findings)
+}
+
+func TestLintOptions(t *testing.T) {
+	doc := loadSpec(t)
+
+	report := Lint(doc,
+		DisableRules("no-inline-response-schema", "path-params-declared"),
+		WithSeverity("operation-tags", SeverityError),
+		WithSeverity("operation-4xx-response", SeverityOff),
+	)
+	require.Equal(t, []string{
+		"warning operation-description /paths/~1pets~1{id}/get",
+		"error operation-tags /paths/~1pets~1{id}/get",
+		"warning operation-id-camel-case /paths/~1pets~1{id}/get/operationId",
+	}, findings(report))
+
+	// Without the x-lint-ignore extensions
+	delete(doc.Paths["/petOwners/{ownerId}"].Extensions, IgnoreExtension)
+	delete(doc.Paths["/pets"].Get.Extensions, IgnoreExtension)
+	report = Lint(doc, DisableRules("no-inline-response-schema", "path-params-declared", "operation-description", "operation-tags"))
+	require.Equal(t, []string{
+		"warning path-kebab-case /paths/~1petOwners~1{ownerId}",
+		"error operation-id-unique /paths/~1pets/get/operationId",
+		"warning operation-id-camel-case /paths/~1pets~1{id}/get/operationId",
+		"warning operation-4xx-response /paths/~1pets~1{id}/get/responses",
+	}, findings(report))
+	require.Equal(t, `operationId "listPets" is already used by GET /petOwners/{ownerId}`, report.Findings[1].Message)
+
+	// Referenced elements ignore rules where they are defined
+	doc.Components.Responses["Error"].Value.Extensions[IgnoreExtension] = json.RawMessage(`["no-inline-response-schema"]`)
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(DefaultRegistry().Rule("no-inline-response-schema")))
+	report = Lint(doc, WithRegistry(registry))
+	require.Equal(t, []string{
+		"warning no-inline-response-schema /paths/~1pets~1{id}/get/responses/200/content/application~1json/schema",
+	}, findings(report))
+}
+
+func TestLintCustomRule(t *testing.T) {
+	doc := loadSpec(t)
+
+	rule := &Rule{
+		ID:          "info-contact",
+		Description: "The API must have a contact.",
+		Severity:    SeverityInfo,
+		Check: func(doc *openapi3.T, report Reporter) {
+			if doc.Info.Contact == nil {
+				report("/info", "info has no contact")
+			}
+		},
+	}
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(rule))
+	require.EqualError(t, registry.Register(rule), `rule "info-contact" is already registered`)
+	require.Same(t, rule, registry.Rule("info-contact"))
+
+	report := Lint(doc, WithRegistry(registry))
+	require.Equal(t, []string{"info info-contact /info"}, findings(report))
+
+	registry = DefaultRegistry()
+	require.NoError(t, registry.Register(rule))
+	require.Len(t, registry.Rules(), 9)
+}
+
+func TestMarshalSARIF(t *testing.T) {
+	doc := loadSpec(t)
+
+	report := Lint(doc, DisableRules("no-inline-response-schema", "path-params-declared", "operation-4xx-response"))
+	data, err := report.MarshalSARIF()
+	require.NoError(t, err)
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	require.Equal(t, "openapi3lint", run.Tool.Driver.Name)
+	var ids []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+	}
+	require.Equal(t, "operation-description,operation-id-camel-case,operation-id-unique,operation-tags,path-kebab-case", strings.Join(ids, ","))
+
+	require.Len(t, run.Results, 3)
+	result := run.Results[0]
+	require.Equal(t, "operation-description", result.RuleID)
+	require.Equal(t, 0, result.RuleIndex)
+	require.Equal(t, "warning", result.Level)
+	require.Equal(t, "operation has no description", result.Message.Text)
+	require.Equal(t, 25, result.Locations[0].PhysicalLocation.Region.StartLine)
+	require.Equal(t, "/paths/~1pets~1{id}/get", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
@@ -0,0 +1,266 @@
+package openapi3lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func builtinRules() []*Rule {
+	return []*Rule{
+		{
+			ID:          "operation-id-unique",
+			Description: "Operations must have distinct operationIds.",
+			Severity:    SeverityError,
+			Check:       checkOperationIDUnique,
+		},
+		{
+			ID:          "operation-id-camel-case",
+			Description: "OperationIds must be camelCase.",
+			Severity:    SeverityWarning,
+			Check:       checkOperationIDCamelCase,
+		},
+		{
+			ID:          "operation-description",
+			Description: "Operations must have a description.",
+			Severity:    SeverityWarning,
+			Check:       checkOperationDescription,
+		},
+		{
+			ID:          "operation-tags",
+			Description: "Operations must have at least one tag.",
+			Severity:    SeverityWarning,
+			Check:       checkOperationTags,
+		},
+		{
+			ID:          "operation-4xx-response",
+			Description: "Operations must document at least one 4xx response.",
+			Severity:    SeverityWarning,
+			Check:       checkOperation4xxResponse,
+		},
+		{
+			ID:          "no-inline-response-schema",
+			Description: "Response schemas must reference a component, or be arrays of one.",
+			Severity:    SeverityWarning,
+			Check:       checkNoInlineResponseSchema,
+		},
+		{
+			ID:          "path-params-declared",
+			Description: "Path templates and path parameters must match.",
+			Severity:    SeverityError,
+			Check:       checkPathParamsDeclared,
+		},
+		{
+			ID:          "path-kebab-case",
+			Description: "Path segments must be kebab-case.",
+			Severity:    SeverityWarning,
+			Check:       checkPathKebabCase,
+		},
+	}
+}
+
+// forEachOperation calls fn with the operations of doc, ordered by path and method.
+func forEachOperation(doc *openapi3.T, fn func(pointer, path, method string, operation *openapi3.Operation)) {
+	for _, path := range sortedPaths(doc) {
+		operations := doc.Paths[path].Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			fn(openapi3.JoinJSONPointer("/paths", path, strings.ToLower(method)), path, method, operations[method])
+		}
+	}
+}
+
+func sortedPaths(doc *openapi3.T) []string {
+	paths := make([]string, 0, len(doc.Paths))
+	for path, pathItem := range doc.Paths {
+		if pathItem != nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func checkOperationIDUnique(doc *openapi3.T, report Reporter) {
+	seen := make(map[string]string)
+	forEachOperation(doc, func(pointer, path, method string, operation *openapi3.Operation) {
+		id := operation.OperationID
+		if id == "" {
+			return
+		}
+		if other, ok := seen[id]; ok {
+			report(openapi3.JoinJSONPointer(pointer, "operationId"), fmt.Sprintf("operationId %q is already used by %s", id, other))
+			return
+		}
+		seen[id] = method + " " + path
+	})
+}
+
+var camelCasePattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func checkOperationIDCamelCase(doc *openapi3.T, report Reporter) {
+	forEachOperation(doc, func(pointer, path, method string, operation *openapi3.Operation) {
+		if id := operation.OperationID; id != "" && !camelCasePattern.MatchString(id) {
+			report(openapi3.JoinJSONPointer(pointer, "operationId"), fmt.Sprintf("operationId %q is not camelCase", id))
+		}
+	})
+}
+
+func checkOperationDescription(doc *openapi3.T, report Reporter) {
+	forEachOperation(doc, func(pointer, path, method string, operation *openapi3.Operation) {
+		if strings.TrimSpace(operation.Description) == "" {
+			report(pointer, "operation has no description")
+		}
+	})
+}
+
+func checkOperationTags(doc *openapi3.T, report Reporter) {
+	forEachOperation(doc, func(pointer, path, method string, operation *openapi3.Operation) {
+		if len(operation.Tags) == 0 {
+			report(pointer, "operation has no tags")
+		}
+	})
+}
+
+func checkOperation4xxResponse(doc *openapi3.T, report Reporter) {
+	forEachOperation(doc, func(pointer, path, method string, operation *openapi3.Operation) {
+		for status := range operation.Responses {
+			if len(status) == 3 && status[0] == '4' {
+				return
+			}
+		}
+		report(openapi3.JoinJSONPointer(pointer, "responses"), "operation documents no 4xx response")
+	})
+}
+
+// checkNoInlineResponseSchema checks the responses of operations and
+// the response components, referenced ones being checked once among the latter.
+func checkNoInlineResponseSchema(doc *openapi3.T, report Reporter) {
+	checkResponse := func(pointer string, response *openapi3.ResponseRef) {
+		if response == nil || response.Ref != "" || response.Value == nil {
+			return
+		}
+		content := response.Value.Content
+		mimes := make([]string, 0, len(content))
+		for mime := range content {
+			mimes = append(mimes, mime)
+		}
+		sort.Strings(mimes)
+		for _, mime := range mimes {
+			if mediaType := content[mime]; mediaType != nil && isInlineSchema(mediaType.Schema) {
+				report(openapi3.JoinJSONPointer(pointer, "content", mime, "schema"), "response schema is defined inline")
+			}
+		}
+	}
+
+	names := make([]string, 0, len(doc.Components.Responses))
+	for name := range doc.Components.Responses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		checkResponse(openapi3.JoinJSONPointer("/components/responses", name), doc.Components.Responses[name])
+	}
+
+	forEachOperation(doc, func(pointer, path, method string, operation *openapi3.Operation) {
+		statuses := make([]string, 0, len(operation.Responses))
+		for status := range operation.Responses {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			checkResponse(openapi3.JoinJSONPointer(pointer, "responses", status), operation.Responses[status])
+		}
+	})
+}
+
+func isInlineSchema(schema *openapi3.SchemaRef) bool {
+	if schema == nil || schema.Ref != "" || schema.Value == nil {
+		return false
+	}
+	if items := schema.Value.Items; schema.Value.Type == "array" && items != nil {
+		return items.Ref == ""
+	}
+	return true
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+func checkPathParamsDeclared(doc *openapi3.T, report Reporter) {
+	for _, path := range sortedPaths(doc) {
+		pathItem := doc.Paths[path]
+		pathPointer := openapi3.JoinJSONPointer("/paths", path)
+		inPath := make(map[string]bool)
+		for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+			inPath[strings.TrimSuffix(match[1], "*")] = true
+		}
+
+		// Parameters of the path item are reported once, where they are declared
+		common := make(map[string]bool)
+		for i, ref := range pathItem.Parameters {
+			if ref == nil || ref.Value == nil || ref.Value.In != openapi3.ParameterInPath {
+				continue
+			}
+			common[ref.Value.Name] = true
+			if !inPath[ref.Value.Name] {
+				report(openapi3.JoinJSONPointer(pathPointer, "parameters", fmt.Sprint(i)),
+					fmt.Sprintf("path parameter %q is not part of the path", ref.Value.Name))
+			}
+		}
+
+		operations := pathItem.Operations()
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			pointer := openapi3.JoinJSONPointer(pathPointer, strings.ToLower(method))
+			declared := make(map[string]bool, len(common))
+			for name := range common {
+				declared[name] = true
+			}
+			for i, ref := range operations[method].Parameters {
+				if ref == nil || ref.Value == nil || ref.Value.In != openapi3.ParameterInPath {
+					continue
+				}
+				declared[ref.Value.Name] = true
+				if !inPath[ref.Value.Name] {
+					report(openapi3.JoinJSONPointer(pointer, "parameters", fmt.Sprint(i)),
+						fmt.Sprintf("path parameter %q is not part of the path", ref.Value.Name))
+				}
+			}
+			names := make([]string, 0, len(inPath))
+			for name := range inPath {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if !declared[name] {
+					report(pointer, fmt.Sprintf("path parameter %q is not declared", name))
+				}
+			}
+		}
+	}
+}
+
+var kebabCasePattern = regexp.MustCompile(`^[a-z0-9.-]*$`)
+
+func checkPathKebabCase(doc *openapi3.T, report Reporter) {
+	for _, path := range sortedPaths(doc) {
+		for _, segment := range strings.Split(path, "/") {
+			// Only the literal parts of a segment are checked
+			if literal := pathParamPattern.ReplaceAllString(segment, ""); !kebabCasePattern.MatchString(literal) {
+				report(openapi3.JoinJSONPointer("/paths", path), fmt.Sprintf("path segment %q is not kebab-case", segment))
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package openapi3lint
+
+import "encoding/json"
+
+// SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html),
+// limited to what code scanning tools read.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel maps a severity to a SARIF level.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// MarshalSARIF returns the report in the SARIF format, for code scanning tools.
+// Findings are located by their JSON pointer, and in their file
+// when the document was loaded with Loader.IncludeSourceLocations.
+func (report *Report) MarshalSARIF() ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "openapi3lint",
+		InformationURI: "https://github.com/getkin/kin-openapi",
+		Rules:          make([]sarifRule, 0, len(report.rules)),
+	}
+	ruleIndexes := make(map[string]int, len(report.rules))
+	for i, rule := range report.rules {
+		ruleIndexes[rule.ID] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifRuleConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: finding.Pointer}},
+		}
+		if l := finding.Location; l != nil {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: l.File},
+				Region:           sarifRegion{StartLine: l.Line, StartColumn: l.Column},
+			}
+		}
+		results = append(results, sarifResult{
+			RuleID:    finding.Rule,
+			RuleIndex: ruleIndexes[finding.Rule],
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	return json.Marshal(&sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
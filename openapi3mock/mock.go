@@ -0,0 +1,377 @@
+// Package openapi3mock serves mock responses described by an OpenAPIv3 spec.
+//
+// Requests are matched with a routers.Router and validated with openapi3filter,
+// then answered with a documented example or, lacking one,
+// a value generated from the response's schema with openapi3example.
+//
+// Clients may pick the response with a Prefer header:
+//
+//	Prefer: code=404, example=notFound
+//
+// where code selects the response status and example names an entry of the
+// media type's examples.
+package openapi3mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3example"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Handler is an http.Handler answering requests with mock responses.
+type Handler struct {
+	Router routers.Router
+
+	// Options used to validate requests.
+	// Authentication always succeeds when nil.
+	Options *openapi3filter.Options
+
+	// ErrorEncoder writes routing, validation and mocking errors,
+	// with a ValidationErrorEncoder over DefaultErrorEncoder when nil.
+	ErrorEncoder openapi3filter.ErrorEncoder
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a mock server matching requests with the given router.
+func NewHandler(router routers.Router) *Handler {
+	return &Handler{
+		Router: router,
+		Options: &openapi3filter.Options{
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+		ErrorEncoder: defaultErrorEncoder,
+	}
+}
+
+var defaultErrorEncoder = (&openapi3filter.ValidationErrorEncoder{Encoder: openapi3filter.DefaultErrorEncoder}).Encode
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := h.serve(ctx, w, r); err != nil {
+		encode := h.ErrorEncoder
+		if encode == nil {
+			encode = defaultErrorEncoder
+		}
+		encode(ctx, err, w)
+	}
+}
+
+func (h *Handler) serve(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	route, pathParams, err := h.Router.FindRoute(r)
+	if err != nil {
+		return err
+	}
+
+	options := h.Options
+	if options == nil {
+		options = &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	}
+	if err = openapi3filter.ValidateRequest(ctx, &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    options,
+	}); err != nil {
+		return err
+	}
+
+	prefer := parsePrefer(r.Header.Values("Prefer"))
+	status, response, err := selectResponse(route.Operation.Responses, prefer.code)
+	if err != nil {
+		return err
+	}
+
+	header := w.Header()
+	for _, name := range sortedKeys(response.Headers) {
+		if http.CanonicalHeaderKey(name) == "Content-Type" {
+			continue
+		}
+		ref := response.Headers[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if value, ok := headerExample(ref.Value); ok {
+			header.Set(name, formatHeader(value))
+		}
+	}
+
+	mime, mediaType := selectContent(response.Content, r.Header.Get("Accept"))
+	if mediaType == nil {
+		w.WriteHeader(status)
+		return nil
+	}
+	value, err := selectExample(mediaType, prefer.example)
+	if err != nil {
+		return err
+	}
+	body, err := encode(mime, value)
+	if err != nil {
+		return err
+	}
+	header.Set("Content-Type", mime)
+	w.WriteHeader(status)
+	w.Write(body)
+	return nil
+}
+
+type preference struct {
+	code    int
+	example string
+}
+
+// parsePrefer reads the code and example preferences of Prefer headers.
+// Unknown preferences are ignored.
+func parsePrefer(values []string) (prefer preference) {
+	for _, value := range values {
+		for _, token := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+			i := strings.IndexByte(token, '=')
+			if i < 0 {
+				continue
+			}
+			k := strings.ToLower(strings.TrimSpace(token[:i]))
+			v := strings.Trim(strings.TrimSpace(token[i+1:]), `"`)
+			switch k {
+			case "code":
+				if code, err := strconv.Atoi(v); err == nil {
+					prefer.code = code
+				}
+			case "example":
+				prefer.example = v
+			}
+		}
+	}
+	return
+}
+
+// selectResponse returns the response for the preferred status code,
+// or the lowest documented success when code is zero.
+func selectResponse(responses openapi3.Responses, code int) (int, *openapi3.Response, error) {
+	if code != 0 && (code < 100 || code > 599) {
+		return 0, nil, &openapi3filter.ValidationError{
+			Status: http.StatusBadRequest,
+			Title:  fmt.Sprintf("preferred status code %d is not between 100 and 599", code),
+		}
+	}
+	if code != 0 {
+		for _, key := range []string{strconv.Itoa(code), fmt.Sprintf("%dXX", code/100), "default"} {
+			if ref := responses[key]; ref != nil && ref.Value != nil {
+				return code, ref.Value, nil
+			}
+		}
+		return 0, nil, &openapi3filter.ValidationError{
+			Status: http.StatusBadRequest,
+			Title:  fmt.Sprintf("no response is documented for status code %d", code),
+		}
+	}
+
+	best, bestKey := 0, ""
+	for key, ref := range responses {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		status := statusOf(key)
+		if status == 0 {
+			continue
+		}
+		if best == 0 || rank(status, key) < rank(best, bestKey) {
+			best, bestKey = status, key
+		}
+	}
+	if best == 0 {
+		if ref := responses.Default(); ref != nil && ref.Value != nil {
+			return http.StatusOK, ref.Value, nil
+		}
+		return 0, nil, &openapi3filter.ValidationError{
+			Status: http.StatusNotImplemented,
+			Title:  "no response is documented",
+		}
+	}
+	return best, responses[bestKey].Value, nil
+}
+
+// statusOf returns the status code a response key stands for:
+// 404 for "404", 400 for "4XX" and 0 otherwise.
+func statusOf(key string) int {
+	if len(key) == 3 && strings.HasSuffix(strings.ToUpper(key), "XX") {
+		key = key[:1] + "00"
+	}
+	status, err := strconv.Atoi(key)
+	if err != nil {
+		return 0
+	}
+	return status
+}
+
+// rank orders statuses: successes first, then explicit codes before ranges.
+func rank(status int, key string) int {
+	r := status
+	if status < 200 || status >= 300 {
+		r += 1000
+	}
+	if statusOf(key) != 0 && strings.HasSuffix(strings.ToUpper(key), "XX") {
+		r += 100
+	}
+	return r
+}
+
+// selectContent picks the first content matching the Accept header,
+// falling back to JSON or to the first content type.
+func selectContent(content openapi3.Content, accept string) (string, *openapi3.MediaType) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	mimes := sortedKeys(content)
+	for _, accepted := range strings.Split(accept, ",") {
+		if i := strings.IndexByte(accepted, ';'); i >= 0 {
+			accepted = accepted[:i]
+		}
+		accepted = strings.ToLower(strings.TrimSpace(accepted))
+		if accepted == "" {
+			continue
+		}
+		for _, mime := range mimes {
+			if mediaRangeMatches(accepted, mime) {
+				return mime, content[mime]
+			}
+		}
+	}
+	if mediaType := content["application/json"]; mediaType != nil {
+		return "application/json", mediaType
+	}
+	return mimes[0], content[mimes[0]]
+}
+
+func mediaRangeMatches(mediaRange, mime string) bool {
+	if mediaRange == "*/*" || mediaRange == mime {
+		return true
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(mime, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
+
+// selectExample returns the named example, or the documented example,
+// or a value generated from the schema.
+func selectExample(mediaType *openapi3.MediaType, name string) (interface{}, error) {
+	if name != "" {
+		ref := mediaType.Examples[name]
+		if ref == nil || ref.Value == nil {
+			return nil, &openapi3filter.ValidationError{
+				Status: http.StatusBadRequest,
+				Title:  fmt.Sprintf("no example named %q is documented", name),
+			}
+		}
+		return ref.Value.Value, nil
+	}
+	if mediaType.Example != nil {
+		return mediaType.Example, nil
+	}
+	for _, name := range sortedKeys(mediaType.Examples) {
+		if ref := mediaType.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+			return ref.Value.Value, nil
+		}
+	}
+	if ref := mediaType.Schema; ref != nil && ref.Value != nil {
+		return openapi3example.Generate(ref.Value, openapi3example.UseExamples())
+	}
+	return nil, nil
+}
+
+// headerExample returns a value for a response header:
+// its example when there is one, a generated value when it is required.
+func headerExample(header *openapi3.Header) (interface{}, bool) {
+	if header.Example != nil {
+		return header.Example, true
+	}
+	for _, name := range sortedKeys(header.Examples) {
+		if ref := header.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+			return ref.Value.Value, true
+		}
+	}
+	var schema *openapi3.Schema
+	if ref := header.Schema; ref != nil {
+		schema = ref.Value
+	}
+	if schema == nil {
+		return nil, false
+	}
+	if schema.Example != nil {
+		return schema.Example, true
+	}
+	if !header.Required {
+		return nil, false
+	}
+	value, err := openapi3example.Generate(schema)
+	return value, err == nil
+}
+
+func formatHeader(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatHeader(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func encode(mime string, value interface{}) ([]byte, error) {
+	if isJSON(mime) {
+		return json.Marshal(value)
+	}
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case nil:
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+func isJSON(mime string) bool {
+	return mime == "application/json" || strings.HasSuffix(mime, "+json")
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case openapi3.Headers:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case openapi3.Content:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case openapi3.Examples:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,247 @@
+package openapi3mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+      responses:
+        '200':
+          description: A pet
+          headers:
+            X-Request-Id:
+              required: true
+              schema:
+                type: string
+                format: uuid
+            X-Rate-Limit:
+              schema:
+                type: integer
+                example: 100
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              examples:
+                dog:
+                  value: {name: Rex, kind: dog}
+                cat:
+                  value: {name: Kitty, kind: cat}
+            text/plain:
+              example: Kitty the cat
+        '404':
+          description: Not found
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [code, message]
+                properties:
+                  code:
+                    type: integer
+                    minimum: 404
+                  message:
+                    type: string
+                    minLength: 10
+        default:
+          description: Error
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: Created
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        kind:
+          type: string
+          enum: [cat, dog]
+`
+
+func TestHandler(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.NoError(t, err)
+	router, err := legacyrouter.NewRouter(doc)
+	require.NoError(t, err)
+	h := NewHandler(router)
+
+	do := func(method, path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		status int
+		ct     string
+		body   string
+	}{
+		{
+			name:   "first example",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			status: http.StatusOK,
+			ct:     "application/json",
+			body:   `{"name":"Kitty","kind":"cat"}`,
+		},
+		{
+			name:   "named example",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Prefer": {"example=dog"}},
+			status: http.StatusOK,
+			ct:     "application/json",
+			body:   `{"name":"Rex","kind":"dog"}`,
+		},
+		{
+			name:   "accepted content type",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Accept": {"text/*;q=0.9"}},
+			status: http.StatusOK,
+			ct:     "text/plain",
+			body:   `Kitty the cat`,
+		},
+		{
+			name:   "preferred code with generated body",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Prefer": {"code=404"}},
+			status: http.StatusNotFound,
+			ct:     "application/json",
+		},
+		{
+			name:   "preferred code falling back to default",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Prefer": {"code=503"}},
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "preferred code out of range",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Prefer": {"code=42"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "preferred code above range",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Prefer": {"code=600"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "missing request body",
+			method: http.MethodPost,
+			path:   "/pets",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid path parameter",
+			method: http.MethodGet,
+			path:   "/pets/one",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown example",
+			method: http.MethodGet,
+			path:   "/pets/1",
+			header: http.Header{"Prefer": {"example=bird"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown path",
+			method: http.MethodGet,
+			path:   "/owners",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.method, tt.path, tt.header)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.ct != "" {
+				require.Equal(t, tt.ct, w.Header().Get("Content-Type"))
+			}
+			if tt.body != "" {
+				if tt.ct == "application/json" {
+					require.JSONEq(t, tt.body, w.Body.String())
+				} else {
+					require.Equal(t, tt.body, w.Body.String())
+				}
+			}
+		})
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		w := do(http.MethodGet, "/pets/1", nil)
+		require.Regexp(t, `^[0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}$`, w.Header().Get("X-Request-Id"))
+		require.Equal(t, "100", w.Header().Get("X-Rate-Limit"))
+	})
+
+	t.Run("generated body is valid", func(t *testing.T) {
+		w := do(http.MethodGet, "/pets/1", http.Header{"Prefer": {"code=404"}})
+		var value interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &value)
+		require.NoError(t, err)
+		schema := doc.Paths["/pets/{id}"].Get.Responses.Get(404).Value.Content.Get("application/json").Schema.Value
+		err = schema.VisitJSON(value)
+		require.NoError(t, err)
+	})
+
+	t.Run("errors without an encoder", func(t *testing.T) {
+		h := &Handler{Router: router}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestParsePrefer(t *testing.T) {
+	require.Equal(t, preference{code: 404, example: "missing"}, parsePrefer([]string{`code=404, example="missing"`}))
+	require.Equal(t, preference{code: 201}, parsePrefer([]string{"respond-async; code=201"}))
+	require.Equal(t, preference{}, parsePrefer([]string{"code=abc"}))
+}
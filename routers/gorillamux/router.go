@@ -2,8 +2,10 @@
 //
 // It differs from the legacy router:
 // * it provides somewhat granular errors: "path not found", "method not allowed".
-// * it handles matching routes with extensions (e.g. /books/{id}.json)
+// * it handles matching routes with extensions (e.g. /books/{id}.json), tried before the routes they overlap
+// and matrix or label path parameters (e.g. /cars{color}{size})
 // * it handles path patterns with a different syntax (e.g. /params/{x}/{y}/{z:.*})
+// * it can report overlapping paths and reject ambiguous ones (see routers.Option)
 package gorillamux
 
 import (
@@ -26,7 +28,41 @@ type Router struct {
 // NewRouter creates a gorilla/mux router.
 // Assumes spec is .Validate()d
 // TODO: Handle/HandlerFunc + ServeHTTP (When there is a match, the route variables can be retrieved calling mux.Vars(request))
-func NewRouter(doc *openapi3.T) (routers.Router, error) {
+func NewRouter(doc *openapi3.T, opts ...routers.Option) (routers.Router, error) {
+	ordered := orderedPaths(doc.Paths)
+	// Routes are tried in order
+	order := make(map[string]int, len(ordered))
+	for i, path := range ordered {
+		order[path] = i
+	}
+	precedence := func(method, a, b, path string) string {
+		if order[b] < order[a] {
+			return b
+		}
+		return a
+	}
+	// Paths matching only requests that another path matches are tried first
+	// (e.g. /books/{id}.json before /books/{id})
+	for range ordered {
+		moved := false
+		for _, overlap := range routers.FindOverlaps(doc.Paths, precedence) {
+			if overlap.Shadowed {
+				ordered = moveBefore(ordered, overlap.Other, overlap.Preferred)
+				for i, path := range ordered {
+					order[path] = i
+				}
+				moved = true
+				break
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	if err := routers.CheckOverlaps(doc.Paths, precedence, opts...); err != nil {
+		return nil, err
+	}
+
 	type srv struct {
 		schemes    []string
 		host, base string
@@ -38,7 +74,12 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 		scheme0 := strings.Split(serverURL, "://")[0]
 		schemes := permutePart(scheme0, server)
 
-		u, err := url.Parse(bEncode(strings.Replace(serverURL, scheme0+"://", schemes[0]+"://", 1)))
+		serverURL = strings.Replace(serverURL, scheme0+"://", schemes[0]+"://", 1)
+		// Server variables are told apart from the path parameters of the same name
+		for name := range server.Variables {
+			serverURL = strings.Replace(serverURL, "{"+name+"}", "{"+serverVariablePrefix+name+"}", -1)
+		}
+		u, err := url.Parse(bEncode(serverURL))
 		if err != nil {
 			return nil, err
 		}
@@ -58,7 +99,7 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 	}
 	muxRouter := mux.NewRouter() /*.UseEncodedPath()?*/
 	r := &Router{}
-	for _, path := range orderedPaths(doc.Paths) {
+	for _, path := range ordered {
 		pathItem := doc.Paths[path]
 
 		operations := pathItem.Operations()
@@ -68,8 +109,10 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 		}
 		sort.Strings(methods)
 
+		template := styledTemplate(path, pathItem, operations, methods)
+
 		for _, s := range servers {
-			muxRoute := muxRouter.Path(s.base + path).Methods(methods...)
+			muxRoute := muxRouter.Path(s.base + template).Methods(methods...)
 			if schemes := s.schemes; len(schemes) != 0 {
 				muxRoute.Schemes(schemes...)
 			}
@@ -93,7 +136,13 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 	return r, nil
 }
 
-// FindRoute extracts the route and parameters of an http.Request
+// serverVariablePrefix prefixes the names of server variables in gorilla/mux templates.
+const serverVariablePrefix = "server."
+
+// FindRoute extracts the route and parameters of an http.Request.
+//
+// Deprecated behavior: the returned path parameters also hold the values of the server's
+// variables, unless a path parameter has the same name. Use Route.ServerVariables instead.
 func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
 	for i, muxRoute := range r.muxes {
 		var match mux.RouteMatch
@@ -101,21 +150,78 @@ func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string
 			if err := match.MatchErr; err != nil {
 				// What then?
 			}
-			route := r.routes[i]
+			route := *r.routes[i]
+			if server := route.Server; server != nil {
+				// Schemes are not matched as variables: theirs take their default value
+				values := make(map[string]string, len(server.Variables))
+				for name := range server.Variables {
+					if value, ok := match.Vars[serverVariablePrefix+name]; ok {
+						values[name] = value
+					}
+				}
+				var ok bool
+				if route.ServerURL, route.ServerVariables, ok = routers.ResolveServer(server, values); !ok {
+					continue
+				}
+			}
 			route.Method = req.Method
 			route.Operation = route.Spec.Paths[route.Path].GetOperation(route.Method)
-			return route, match.Vars, nil
+			return &route, pathParams(match.Vars), nil
 		}
 		switch match.MatchErr {
 		case nil:
 		case mux.ErrMethodMismatch:
-			return nil, nil, routers.ErrMethodNotAllowed
+			route := r.routes[i]
+			return nil, nil, routers.NewMethodNotAllowedError(route.Path, route.PathItem)
 		default: // What then?
 		}
 	}
 	return nil, nil, routers.ErrPathNotFound
 }
 
+// styledTemplate gives the variables of path matching the style of their parameters
+// the syntax of gorilla/mux. Variables of operations with different styles match either.
+func styledTemplate(path string, pathItem *openapi3.PathItem, operations map[string]*openapi3.Operation, methods []string) string {
+	operationPatterns := make([]map[string]string, 0, len(methods))
+	for _, method := range methods {
+		operationPatterns = append(operationPatterns, routers.PathParameterPatterns(pathItem, operations[method]))
+	}
+	alternatives := make(map[string][]string)
+	for _, patterns := range operationPatterns {
+		for name := range patterns {
+			if _, ok := alternatives[name]; ok {
+				continue
+			}
+			for _, others := range operationPatterns {
+				pattern, ok := others[name]
+				if !ok {
+					pattern = "[^/]+"
+				}
+				if !containsString(alternatives[name], pattern) {
+					alternatives[name] = append(alternatives[name], pattern)
+				}
+			}
+		}
+	}
+	for name, patterns := range alternatives {
+		pattern := patterns[0]
+		if len(patterns) > 1 {
+			pattern = "(?:" + strings.Join(patterns, "|") + ")"
+		}
+		path = strings.Replace(path, "{"+name+"}", "{"+name+":"+pattern+"}", -1)
+	}
+	return path
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func orderedPaths(paths map[string]*openapi3.PathItem) []string {
 	// https://github.com/OAI/OpenAPI-Specification/blob/master/versions/3.0.3.md#pathsObject
 	// When matching URLs, concrete (non-templated) paths would be matched
@@ -141,9 +247,42 @@ func orderedPaths(paths map[string]*openapi3.PathItem) []string {
 	return ordered
 }
 
+// moveBefore moves path right before other in ordered.
+func moveBefore(ordered []string, path, other string) []string {
+	moved := make([]string, 0, len(ordered))
+	for _, p := range ordered {
+		switch p {
+		case path:
+		case other:
+			moved = append(moved, path, other)
+		default:
+			moved = append(moved, p)
+		}
+	}
+	return moved
+}
+
 // Magic strings that temporarily replace "{}" so net/url.Parse() works
 var blURL, brURL = strings.Repeat("-", 50), strings.Repeat("_", 50)
 
+// pathParams returns the path parameters of matched variables,
+// along with the server variables whose names no path parameter has.
+func pathParams(vars map[string]string) map[string]string {
+	params := make(map[string]string, len(vars))
+	for name, value := range vars {
+		if !strings.HasPrefix(name, serverVariablePrefix) {
+			params[name] = value
+		}
+	}
+	for name, value := range vars {
+		name = strings.TrimPrefix(name, serverVariablePrefix)
+		if _, ok := params[name]; !ok {
+			params[name] = value
+		}
+	}
+	return params
+}
+
 func bEncode(s string) string {
 	s = strings.Replace(s, "{", blURL, -1)
 	s = strings.Replace(s, "}", brURL, -1)
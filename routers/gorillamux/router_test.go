@@ -174,11 +174,22 @@ func TestRouter(t *testing.T) {
 	expect(r, http.MethodGet, "https:///api/v1/hello", nil, nil)
 	expect(r, http.MethodGet, "https://www.example.com/hello", nil, nil)
 	expect(r, http.MethodGet, "https://www.example.com/api/v1/hello", helloGET, nil)
-	expect(r, http.MethodGet, "https://domain0.domain1.com/api/v1/hello", helloGET, map[string]string{
+	// Server variables take enumerated values only
+	expect(r, http.MethodGet, "https://domain0.domain1.com/api/v1/hello", nil, nil)
+	expect(r, http.MethodGet, "https://domain0.example.com/api/v1/hello", helloGET, map[string]string{
 		"d0": "domain0",
-		"d1": "domain1",
+		"d1": "example",
 		// "scheme": "https", TODO: https://github.com/gorilla/mux/issues/624
 	})
+	{
+		req, err := http.NewRequest(http.MethodGet, "https://domain0.example.com/api/v1/hello", nil)
+		require.NoError(t, err)
+		route, _, err := r.FindRoute(req)
+		require.NoError(t, err)
+		require.Equal(t, doc.Servers[1], route.Server)
+		require.Equal(t, "https://domain0.example.com/api/v1/", route.ServerURL)
+		require.Equal(t, map[string]string{"scheme": "https", "d0": "domain0", "d1": "example"}, route.ServerVariables)
+	}
 
 	{
 		uri := "https://www.example.com/api/v1/onlyGET"
@@ -188,6 +199,8 @@ func TestRouter(t *testing.T) {
 		require.NotNil(t, req)
 		route, pathParams, err := r.FindRoute(req)
 		require.EqualError(t, err, routers.ErrMethodNotAllowed.Error())
+		require.Equal(t, "/onlyGET", err.(*routers.RouteError).Path)
+		require.Equal(t, []string{http.MethodGet}, err.(*routers.RouteError).AllowedMethods)
 		require.Nil(t, route)
 		require.Nil(t, pathParams)
 	}
@@ -4,6 +4,7 @@
 //   * "/"
 //   * "/abc""
 //   * "/abc/{variable}" (matches until next '/' or end-of-string)
+//   * "/abc/{name}.{ext}" (variables leave what follows them in their segment to the next patterns)
 //   * "/abc/{variable*}" (matches everything, including "/abc" if "/abc" has noot)
 //   * "/abc/{ variable | prefix_(.*}_suffix }" (matches regular expressions)
 package pathpattern
@@ -270,6 +271,17 @@ func (currentNode *Node) Match(path string) (*Node, []string) {
 	return currentNode.matchRemaining(path, variableValues)
 }
 
+// continuesSegment tells whether some suffixes of the node can match
+// the rest of the path segment of a variable.
+func (currentNode *Node) continuesSegment() bool {
+	for _, suffix := range currentNode.Suffixes {
+		if suffix.Kind != SuffixKindConstant || !strings.HasPrefix(suffix.Pattern, "/") {
+			return true
+		}
+	}
+	return false
+}
+
 func (currentNode *Node) matchRemaining(remaining string, paramValues []string) (*Node, []string) {
 	// Check if this node matches
 	if len(remaining) == 0 && currentNode.Value != nil {
@@ -294,6 +306,15 @@ func (currentNode *Node) matchRemaining(remaining string, paramValues []string)
 			if i < 0 {
 				i = len(remaining)
 			}
+			if suffix.Node.continuesSegment() {
+				// Try the longest values leaving some of the segment first
+				for j := i - 1; j > 0; j-- {
+					newParamValues := append(paramValues, remaining[:j])
+					if resultNode, resultValues = suffix.Node.matchRemaining(remaining[j:], newParamValues); resultNode != nil {
+						return resultNode, resultValues
+					}
+				}
+			}
 			newParamValues := append(paramValues, remaining[:i])
 			newRemaining := remaining[i:]
 			resultNode, resultValues = suffix.Node.matchRemaining(newRemaining, newParamValues)
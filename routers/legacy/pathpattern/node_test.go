@@ -20,6 +20,9 @@ func TestPatterns(t *testing.T) {
 	add("/abc/{fileName|(.*)\\.jpeg}", "JPEG")
 	add("/abc/{fileName|some_prefix_(.*)\\.jpeg}", "PREFIXED JPEG")
 	add("/root/{path*}", "DIRECTORY")
+	add("/files/{name}.{ext}", "FILE WITH EXTENSION")
+	add("/files/{name}", "FILE WITHOUT EXTENSION")
+	add("/reports/{year}-{month}/{day}", "REPORT")
 	add("/impossible_route", "IMPOSSIBLE")
 
 	add(PathFromHost("www.nike.com", true), "WWW-HOST")
@@ -58,6 +61,11 @@ func TestPatterns(t *testing.T) {
 	expect("/abc/someFile.jpeg", "JPEG", "someFile")
 	expect("/abc/someFile.old.jpeg", "JPEG", "someFile.old")
 	expect("/abc/some_prefix_someFile.jpeg", "PREFIXED JPEG", "someFile")
+	expect("/files/report.tar.gz", "FILE WITH EXTENSION", "report.tar", "gz")
+	expect("/files/report", "FILE WITHOUT EXTENSION", "report")
+	expect("/files/.gz", "FILE WITHOUT EXTENSION", ".gz")
+	expect("/reports/2021-06/12", "REPORT", "2021", "06", "12")
+	expect("/reports/2021/12", "not found")
 
 	expect("/root", "DIRECTORY", "")
 	expect("/root/", "DIRECTORY", "")
@@ -2,8 +2,9 @@
 //
 // It differs from the gorilla/mux router:
 // * it provides granular errors: "path not found", "method not allowed", "variable missing from path"
-// * it does not handle matching routes with extensions (e.g. /books/{id}.json)
+// * it handles matching routes with extensions (e.g. /books/{id}.json), but not matrix or label path parameters next to other variables
 // * it handles path patterns with a different syntax (e.g. /params/{x}/{y}/{z.*})
+// * it can report overlapping paths and reject ambiguous ones (see routers.Option)
 package legacy
 
 import (
@@ -18,6 +19,18 @@ import (
 	"github.com/getkin/kin-openapi/routers/legacy/pathpattern"
 )
 
+var allMethods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
 // Routers maps a HTTP request to a Router.
 type Routers []*Router
 
@@ -58,10 +71,13 @@ type Router struct {
 //
 // If the given OpenAPIv3 document has servers, router will use them.
 // All operations of the document will be added to the router.
-func NewRouter(doc *openapi3.T) (routers.Router, error) {
+func NewRouter(doc *openapi3.T, opts ...routers.Option) (routers.Router, error) {
 	if err := doc.Validate(context.Background()); err != nil {
 		return nil, fmt.Errorf("validating OpenAPI failed: %v", err)
 	}
+	if err := routers.CheckOverlaps(doc.Paths, precedence, opts...); err != nil {
+		return nil, err
+	}
 	router := &Router{doc: doc}
 	root := router.node()
 	for path, pathItem := range doc.Paths {
@@ -81,6 +97,21 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 	return router, nil
 }
 
+// precedence matches path against a node of templates a and b only.
+func precedence(method, a, b, path string) string {
+	root := &pathpattern.Node{}
+	for _, template := range []string{a, b} {
+		if err := root.Add(template, template, nil); err != nil {
+			return ""
+		}
+	}
+	if node, _ := root.Match(path); node != nil {
+		template, _ := node.Value.(string)
+		return template
+	}
+	return ""
+}
+
 // AddRoute adds a route in the router.
 func (router *Router) AddRoute(route *routers.Route) error {
 	method := route.Method
@@ -104,7 +135,10 @@ func (router *Router) node() *pathpattern.Node {
 	return root
 }
 
-// FindRoute extracts the route and parameters of an http.Request
+// FindRoute extracts the route and parameters of an http.Request.
+//
+// Deprecated behavior: the returned path parameters also hold the values of the server's
+// variables, path parameters of the same name taking precedence. Use Route.ServerVariables instead.
 func (router *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
 	method, url := req.Method, req.URL
 	doc := router.doc
@@ -112,24 +146,39 @@ func (router *Router) FindRoute(req *http.Request) (*routers.Route, map[string]s
 	// Get server
 	servers := doc.Servers
 	var server *openapi3.Server
+	var serverURL string
+	var serverVariables map[string]string
 	var remainingPath string
 	var pathParams map[string]string
 	if len(servers) == 0 {
 		remainingPath = url.Path
 	} else {
-		var paramValues []string
-		server, paramValues, remainingPath = servers.MatchURL(url)
+		rawURL := url.String()
+		if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+			rawURL = rawURL[:i]
+		}
+		// Find the first server matching with enumerated values
+		for _, s := range servers {
+			paramValues, remaining, ok := s.MatchRawURL(rawURL)
+			if !ok {
+				continue
+			}
+			values := make(map[string]string, 8)
+			paramNames, _ := s.ParameterNames()
+			for i, value := range paramValues {
+				name := paramNames[i]
+				values[name] = value
+			}
+			if serverURL, serverVariables, ok = routers.ResolveServer(s, values); ok {
+				server, remainingPath, pathParams = s, remaining, values
+				break
+			}
+		}
 		if server == nil {
 			return nil, nil, &routers.RouteError{
 				Reason: routers.ErrPathNotFound.Error(),
 			}
 		}
-		pathParams = make(map[string]string, 8)
-		paramNames, _ := server.ParameterNames()
-		for i, value := range paramValues {
-			name := paramNames[i]
-			pathParams[name] = value
-		}
 	}
 
 	// Get PathItem
@@ -140,13 +189,21 @@ func (router *Router) FindRoute(req *http.Request) (*routers.Route, map[string]s
 		route, _ = node.Value.(*routers.Route)
 	}
 	if route == nil {
-		pathItem := doc.Paths[remainingPath]
-		if pathItem == nil {
-			return nil, nil, &routers.RouteError{Reason: routers.ErrPathNotFound.Error()}
+		if pathItem := doc.Paths[remainingPath]; pathItem != nil {
+			return nil, nil, routers.NewMethodNotAllowedError(remainingPath, pathItem)
 		}
-		if pathItem.GetOperation(method) == nil {
-			return nil, nil, &routers.RouteError{Reason: routers.ErrMethodNotAllowed.Error()}
+		// Look for the path among the routes of the other methods
+		for _, other := range allMethods {
+			if other == method {
+				continue
+			}
+			if node, _ := root.Match(other + " " + remainingPath); node != nil {
+				if route, ok := node.Value.(*routers.Route); ok {
+					return nil, nil, routers.NewMethodNotAllowedError(route.Path, route.PathItem)
+				}
+			}
 		}
+		return nil, nil, &routers.RouteError{Reason: routers.ErrPathNotFound.Error()}
 	}
 
 	if pathParams == nil {
@@ -160,5 +217,12 @@ func (router *Router) FindRoute(req *http.Request) (*routers.Route, map[string]s
 		}
 		pathParams[key] = value
 	}
+	if server != nil {
+		matched := *route
+		matched.Server = server
+		matched.ServerURL = serverURL
+		matched.ServerVariables = serverVariables
+		route = &matched
+	}
 	return route, pathParams, nil
 }
@@ -151,11 +151,18 @@ func TestRouter(t *testing.T) {
 	expect(r, http.MethodGet, "/books/War.and.Peace", paramsGET, map[string]string{
 		"bookid": "War.and.Peace",
 	})
+	expect(r, http.MethodPost, "/books/War.and.Peace.json", booksPOST, map[string]string{
+		"bookid2": "War.and.Peace",
+	})
 	{
-		req, err := http.NewRequest(http.MethodPost, "/books/War.and.Peace.json", nil)
+		req, err := http.NewRequest(http.MethodPut, "/books/War.and.Peace.json", nil)
 		require.NoError(t, err)
 		_, _, err = r.FindRoute(req)
-		require.EqualError(t, err, routers.ErrPathNotFound.Error())
+		require.Equal(t, &routers.RouteError{
+			Reason:         routers.ErrMethodNotAllowed.Error(),
+			Path:           "/books/{bookid}",
+			AllowedMethods: []string{http.MethodGet},
+		}, err)
 	}
 	expect(r, http.MethodPost, "/partial", nil, nil)
 
@@ -176,11 +183,31 @@ func TestRouter(t *testing.T) {
 	expect(r, http.MethodGet, "https:///api/v1/hello", nil, nil)
 	expect(r, http.MethodGet, "https://www.example.com/hello", nil, nil)
 	expect(r, http.MethodGet, "https://www.example.com/api/v1/hello", helloGET, nil)
-	expect(r, http.MethodGet, "https://domain0.domain1.com/api/v1/hello", helloGET, map[string]string{
+	// Server variables take enumerated values only
+	expect(r, http.MethodGet, "https://domain0.domain1.com/api/v1/hello", nil, nil)
+	expect(r, http.MethodGet, "https://domain0.example.com/api/v1/hello", helloGET, map[string]string{
 		"d0": "domain0",
-		"d1": "domain1",
+		"d1": "example",
 	})
 
+	{
+		req, err := http.NewRequest(http.MethodGet, "https://www.example.com/api/v1/hello", nil)
+		require.NoError(t, err)
+		route, _, err := r.FindRoute(req)
+		require.NoError(t, err)
+		require.Equal(t, doc.Servers[0], route.Server)
+		require.Equal(t, "https://www.example.com/api/v1", route.ServerURL)
+		require.Empty(t, route.ServerVariables)
+
+		req, err = http.NewRequest(http.MethodGet, "https://domain0.example.com/api/v1/hello", nil)
+		require.NoError(t, err)
+		route, _, err = r.FindRoute(req)
+		require.NoError(t, err)
+		require.Equal(t, doc.Servers[1], route.Server)
+		require.Equal(t, "https://domain0.example.com/api/v1/", route.ServerURL)
+		require.Equal(t, map[string]string{"d0": "domain0", "d1": "example"}, route.ServerVariables)
+	}
+
 	{
 		uri := "https://www.example.com/api/v1/onlyGET"
 		expect(r, http.MethodGet, uri, helloGET, nil)
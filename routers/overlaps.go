@@ -0,0 +1,351 @@
+package routers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Overlap describes two path templates with an operation for the same method
+// that both match some request paths, such as /users/{id} and /users/me.
+type Overlap struct {
+	Method string
+	// Preferred is the template the router routes requests matching both templates to
+	Preferred string
+	Other     string
+	// Example is a request path matching both templates
+	Example string
+	// Ambiguous is set when neither template is more specific than the other
+	// (e.g. /a/{x}/c and /a/b/{y}), so only the precedence rules of the router decide between them.
+	Ambiguous bool
+	// Shadowed is set when Other only matches request paths that Preferred matches:
+	// its operation for Method is never routed to.
+	Shadowed bool
+}
+
+func (overlap *Overlap) String() string {
+	s := fmt.Sprintf("%s %s and %s overlap (e.g. %s): %s takes precedence",
+		overlap.Method, overlap.Preferred, overlap.Other, overlap.Example, overlap.Preferred)
+	switch {
+	case overlap.Ambiguous:
+		s += " although neither is more specific"
+	case overlap.Shadowed:
+		s += fmt.Sprintf(" although %s is more specific", overlap.Other)
+	}
+	return s
+}
+
+// AmbiguousPathsError is returned when building a router with RejectAmbiguousPaths
+// from a document with ambiguous path templates.
+type AmbiguousPathsError struct {
+	Overlaps []*Overlap
+}
+
+func (err *AmbiguousPathsError) Error() string {
+	buff := &strings.Builder{}
+	buff.WriteString("ambiguous path templates: ")
+	for i, overlap := range err.Overlaps {
+		if i > 0 {
+			buff.WriteString(" | ")
+		}
+		fmt.Fprintf(buff, "%s %s and %s (e.g. %s)", overlap.Method, overlap.Preferred, overlap.Other, overlap.Example)
+	}
+	return buff.String()
+}
+
+// Option configures the construction of a router.
+type Option func(*settings)
+
+type settings struct {
+	overlapReporter      func(*Overlap)
+	rejectAmbiguousPaths bool
+}
+
+// ReportOverlaps makes routers call reporter with each pair of overlapping path templates
+// of the document they are built from.
+func ReportOverlaps(reporter func(*Overlap)) Option {
+	return func(s *settings) { s.overlapReporter = reporter }
+}
+
+// RejectAmbiguousPaths makes building a router fail with an *AmbiguousPathsError
+// when its document has ambiguous path templates.
+func RejectAmbiguousPaths() Option {
+	return func(s *settings) { s.rejectAmbiguousPaths = true }
+}
+
+// Precedence returns which of the path templates a and b a router routes a request
+// to, given its method and a path both templates match.
+type Precedence func(method, a, b, path string) string
+
+// CheckOverlaps applies the options given to the constructor of a router:
+// it reports the overlapping templates of paths and fails on ambiguous ones as requested.
+// Nothing is analyzed when no option asks for it.
+func CheckOverlaps(paths openapi3.Paths, precedence Precedence, opts ...Option) error {
+	s := &settings{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.overlapReporter == nil && !s.rejectAmbiguousPaths {
+		return nil
+	}
+
+	var ambiguous []*Overlap
+	for _, overlap := range FindOverlaps(paths, precedence) {
+		if s.overlapReporter != nil {
+			s.overlapReporter(overlap)
+		}
+		if overlap.Ambiguous {
+			ambiguous = append(ambiguous, overlap)
+		}
+	}
+	if s.rejectAmbiguousPaths && len(ambiguous) != 0 {
+		return &AmbiguousPathsError{Overlaps: ambiguous}
+	}
+	return nil
+}
+
+// FindOverlaps returns the overlapping path templates of paths, by method then template.
+//
+// Variables are taken to match any non-empty part of a path segment,
+// whatever pattern a router may allow in them.
+func FindOverlaps(paths openapi3.Paths, precedence Precedence) []*Overlap {
+	methodPaths := make(map[string][]string)
+	for path, pathItem := range paths {
+		for method := range pathItem.Operations() {
+			methodPaths[method] = append(methodPaths[method], path)
+		}
+	}
+	methods := make([]string, 0, len(methodPaths))
+	for method := range methodPaths {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	templates := make(map[string][]segmentTemplate, len(paths))
+	for path := range paths {
+		templates[path] = splitTemplate(path)
+	}
+
+	var overlaps []*Overlap
+	for _, method := range methods {
+		paths := methodPaths[method]
+		sort.Strings(paths)
+		for i, a := range paths {
+			for _, b := range paths[i+1:] {
+				example, aInB, bInA, ok := overlapTemplates(templates[a], templates[b])
+				if !ok {
+					continue
+				}
+				overlap := &Overlap{
+					Method:    method,
+					Preferred: a,
+					Other:     b,
+					Example:   example,
+					Ambiguous: aInB == bInA,
+					Shadowed:  bInA,
+				}
+				if precedence(method, a, b, example) == b {
+					overlap.Preferred, overlap.Other, overlap.Shadowed = b, a, aInB
+				}
+				overlaps = append(overlaps, overlap)
+			}
+		}
+	}
+	return overlaps
+}
+
+// segmentTemplate is a path segment template compiled to a nondeterministic automaton,
+// each variable being one or more bytes.
+type segmentTemplate struct {
+	text  string
+	elems []segmentElem
+}
+
+type segmentElem struct {
+	kind byte // 'c' for the byte c, '?' for any byte, '*' for any number of bytes
+	c    byte
+}
+
+func splitTemplate(template string) []segmentTemplate {
+	segments := strings.Split(template, "/")
+	compiled := make([]segmentTemplate, 0, len(segments))
+	for _, segment := range segments {
+		t := segmentTemplate{text: segment}
+		for s := segment; s != ""; {
+			if s[0] == '{' {
+				if i := strings.IndexByte(s, '}'); i > 0 {
+					t.elems = append(t.elems, segmentElem{kind: '?'}, segmentElem{kind: '*'})
+					s = s[i+1:]
+					continue
+				}
+			}
+			t.elems = append(t.elems, segmentElem{kind: 'c', c: s[0]})
+			s = s[1:]
+		}
+		compiled = append(compiled, t)
+	}
+	return compiled
+}
+
+// overlapTemplates tells whether templates a and b match a common path, returning an example of one,
+// and whether all paths a matches are matched by b and conversely.
+func overlapTemplates(a, b []segmentTemplate) (example string, aInB, bInA, ok bool) {
+	if len(a) != len(b) {
+		return "", false, false, false
+	}
+	// Quickly tell apart the templates of distinct resources
+	for i := range a {
+		if a[i].text != b[i].text && !strings.Contains(a[i].text, "{") && !strings.Contains(b[i].text, "{") {
+			return "", false, false, false
+		}
+	}
+
+	examples := make([]string, 0, len(a))
+	aInB, bInA = true, true
+	for i := range a {
+		example, segmentAInB, segmentBInA, ok := overlapSegments(a[i], b[i])
+		if !ok {
+			return "", false, false, false
+		}
+		examples = append(examples, example)
+		aInB = aInB && segmentAInB
+		bInA = bInA && segmentBInA
+	}
+	return strings.Join(examples, "/"), aInB, bInA, true
+}
+
+// overlapSegments explores the product of the automata of a and b made deterministic.
+func overlapSegments(a, b segmentTemplate) (example string, aInB, bInA, ok bool) {
+	if a.text == b.text {
+		return strings.Map(func(r rune) rune {
+			if r == '{' || r == '}' {
+				return -1
+			}
+			return r
+		}, a.text), true, true, true
+	}
+
+	// Path bytes are either one of the templates' text or another one,
+	// which comes first so that examples fill variables with it.
+	var alphabet []byte
+	seen := make(map[byte]bool)
+	for _, elems := range [][]segmentElem{a.elems, b.elems} {
+		for _, elem := range elems {
+			if elem.kind == 'c' && !seen[elem.c] {
+				seen[elem.c] = true
+				alphabet = append(alphabet, elem.c)
+			}
+		}
+	}
+	for _, c := range []byte("x0123456789abcdefghijklmnopqrstuvwyz") {
+		if !seen[c] {
+			alphabet = append([]byte{c}, alphabet...)
+			break
+		}
+	}
+
+	type state struct {
+		a, b   []bool
+		parent int
+		c      byte
+	}
+	states := []state{{a: closure(a.elems, start(a.elems)), b: closure(b.elems, start(b.elems)), parent: -1}}
+	visited := map[string]bool{stateKey(states[0].a, states[0].b): true}
+	exampleOf := func(i int) string {
+		var path []byte
+		for ; states[i].parent >= 0; i = states[i].parent {
+			path = append(path, states[i].c)
+		}
+		for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+			path[l], path[r] = path[r], path[l]
+		}
+		return string(path)
+	}
+
+	aInB, bInA = true, true
+	for i := 0; i < len(states); i++ {
+		s := states[i]
+		acceptA, acceptB := s.a[len(a.elems)], s.b[len(b.elems)]
+		switch {
+		case acceptA && acceptB:
+			if !ok {
+				example, ok = exampleOf(i), true
+			}
+		case acceptA:
+			aInB = false
+		case acceptB:
+			bInA = false
+		}
+		for _, c := range alphabet {
+			next := state{a: step(a.elems, s.a, c), b: step(b.elems, s.b, c), parent: i, c: c}
+			key := stateKey(next.a, next.b)
+			if visited[key] || (!anyState(next.a) && !anyState(next.b)) {
+				continue
+			}
+			visited[key] = true
+			states = append(states, next)
+		}
+	}
+	return example, aInB, bInA, ok
+}
+
+func start(elems []segmentElem) []bool {
+	states := make([]bool, len(elems)+1)
+	states[0] = true
+	return states
+}
+
+func closure(elems []segmentElem, states []bool) []bool {
+	for i, elem := range elems {
+		if states[i] && elem.kind == '*' {
+			states[i+1] = true
+		}
+	}
+	return states
+}
+
+func step(elems []segmentElem, states []bool, c byte) []bool {
+	next := make([]bool, len(states))
+	for i, elem := range elems {
+		if !states[i] {
+			continue
+		}
+		switch elem.kind {
+		case 'c':
+			if elem.c == c {
+				next[i+1] = true
+			}
+		case '?':
+			next[i+1] = true
+		case '*':
+			next[i] = true
+		}
+	}
+	return closure(elems, next)
+}
+
+func anyState(states []bool) bool {
+	for _, state := range states {
+		if state {
+			return true
+		}
+	}
+	return false
+}
+
+func stateKey(a, b []bool) string {
+	key := make([]byte, 0, len(a)+len(b)+1)
+	for _, states := range [][]bool{a, b} {
+		for _, state := range states {
+			if state {
+				key = append(key, '1')
+			} else {
+				key = append(key, '0')
+			}
+		}
+		key = append(key, '|')
+	}
+	return string(key)
+}
@@ -0,0 +1,93 @@
+package routers_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/getkin/kin-openapi/routers/radix"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterOverlaps(t *testing.T) {
+	paths := openapi3.Paths{}
+	for _, path := range []string{"/users/me", "/users/{id}", "/a/{x}/c", "/a/b/{y}", "/books/{id}", "/books/{id}.json"} {
+		var params openapi3.Parameters
+		for _, name := range []string{"id", "x", "y"} {
+			if strings.Contains(path, "{"+name+"}") {
+				params = append(params, &openapi3.ParameterRef{Value: openapi3.NewPathParameter(name)})
+			}
+		}
+		paths[path] = &openapi3.PathItem{
+			Get:        &openapi3.Operation{Responses: openapi3.NewResponses()},
+			Parameters: params,
+		}
+	}
+	doc := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "MyAPI", Version: "0.1"}, Paths: paths}
+	err := doc.Validate(context.Background())
+	require.NoError(t, err)
+
+	// Every router reports the precedence it routes with
+	reports := []string{
+		"GET /a/b/{y} and /a/{x}/c overlap (e.g. /a/b/c): /a/b/{y} takes precedence although neither is more specific",
+		"GET /books/{id}.json and /books/{id} overlap (e.g. /books/x.json): /books/{id}.json takes precedence",
+		"GET /users/me and /users/{id} overlap (e.g. /users/me): /users/me takes precedence",
+	}
+	// Template routed to by path, none for errors
+	routes := map[string]string{
+		"/a/b/c":               "/a/b/{y}",
+		"/a/not-b/c":           "/a/{x}/c",
+		"/a/b/not-c":           "/a/b/{y}",
+		"/books/x.json":        "/books/{id}.json",
+		"/books/x.json.json":   "/books/{id}.json",
+		"/books/x.yaml":        "/books/{id}",
+		"/books/.json":         "/books/{id}",
+		"/users/me":            "/users/me",
+		"/users/not-me.json":   "/users/{id}",
+		"/users/me/":           "",
+		"/books/x.json/x.json": "",
+	}
+	// Routes that differ from the above by router
+	exceptions := map[string]map[string]string{
+		// The legacy router ignores trailing slashes
+		"legacy": {"/users/me/": "/users/me"},
+	}
+
+	for name, newRouter := range map[string]func(*openapi3.T, ...routers.Option) (routers.Router, error){
+		"gorillamux": gorillamux.NewRouter,
+		"legacy":     legacyrouter.NewRouter,
+		"radix":      radix.NewRouter,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var reported []string
+			r, err := newRouter(doc, routers.ReportOverlaps(func(overlap *routers.Overlap) {
+				reported = append(reported, overlap.String())
+			}))
+			require.NoError(t, err)
+			require.Equal(t, reports, reported)
+
+			for path, template := range routes {
+				if exception, ok := exceptions[name][path]; ok {
+					template = exception
+				}
+				req, err := http.NewRequest(http.MethodGet, path, nil)
+				require.NoError(t, err)
+				route, _, err := r.FindRoute(req)
+				if template == "" {
+					require.Error(t, err, path)
+					continue
+				}
+				require.NoError(t, err, path)
+				require.Equal(t, template, route.Path, path)
+			}
+
+			_, err = newRouter(doc, routers.RejectAmbiguousPaths())
+			require.EqualError(t, err, "ambiguous path templates: GET /a/b/{y} and /a/{x}/c (e.g. /a/b/c)")
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	get := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	paths := openapi3.Paths{
+		"/users/{id}":       &openapi3.PathItem{Get: get, Delete: get},
+		"/users/me":         &openapi3.PathItem{Get: get},
+		"/users/{id}/posts": &openapi3.PathItem{Get: get},
+		"/a/{x}/c":          &openapi3.PathItem{Get: get},
+		"/a/b/{y}":          &openapi3.PathItem{Get: get},
+		"/books/{id}":       &openapi3.PathItem{Get: get},
+		"/books/{id}.json":  &openapi3.PathItem{Get: get},
+		"/files/{a}.{b}":    &openapi3.PathItem{Get: get},
+		"/files/x{a}":       &openapi3.PathItem{Get: get},
+		"/files/{a}.pdf":    &openapi3.PathItem{Get: get},
+	}
+	// Prefers the first template in lexicographical order
+	first := func(method, a, b, path string) string {
+		if b < a {
+			return b
+		}
+		return a
+	}
+
+	overlaps := FindOverlaps(paths, first)
+	require.Equal(t, []*Overlap{
+		{Method: "GET", Preferred: "/a/b/{y}", Other: "/a/{x}/c", Example: "/a/b/c", Ambiguous: true},
+		{Method: "GET", Preferred: "/books/{id}", Other: "/books/{id}.json", Example: "/books/x.json", Shadowed: true},
+		{Method: "GET", Preferred: "/files/x{a}", Other: "/files/{a}.pdf", Example: "/files/x.pdf", Ambiguous: true},
+		{Method: "GET", Preferred: "/files/x{a}", Other: "/files/{a}.{b}", Example: "/files/x.0", Ambiguous: true},
+		{Method: "GET", Preferred: "/files/{a}.pdf", Other: "/files/{a}.{b}", Example: "/files/x.pdf"},
+		{Method: "GET", Preferred: "/users/me", Other: "/users/{id}", Example: "/users/me"},
+	}, overlaps)
+
+	require.Equal(t, "GET /a/b/{y} and /a/{x}/c overlap (e.g. /a/b/c): /a/b/{y} takes precedence although neither is more specific", overlaps[0].String())
+	require.Equal(t, "GET /books/{id} and /books/{id}.json overlap (e.g. /books/x.json): /books/{id} takes precedence although /books/{id}.json is more specific", overlaps[1].String())
+	require.Equal(t, "GET /users/me and /users/{id} overlap (e.g. /users/me): /users/me takes precedence", overlaps[5].String())
+}
+
+func TestCheckOverlaps(t *testing.T) {
+	get := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	paths := openapi3.Paths{
+		"/users/{id}": &openapi3.PathItem{Get: get},
+		"/users/me":   &openapi3.PathItem{Get: get},
+		"/a/{x}/c":    &openapi3.PathItem{Get: get},
+		"/a/b/{y}":    &openapi3.PathItem{Get: get},
+	}
+	precedence := func(method, a, b, path string) string {
+		panic("overlaps should not be analyzed")
+	}
+	err := CheckOverlaps(paths, precedence)
+	require.NoError(t, err)
+
+	precedence = func(method, a, b, path string) string { return a }
+	var reported []string
+	err = CheckOverlaps(paths, precedence, ReportOverlaps(func(overlap *Overlap) {
+		reported = append(reported, overlap.Preferred+" "+overlap.Other)
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"/a/b/{y} /a/{x}/c", "/users/me /users/{id}"}, reported)
+
+	err = CheckOverlaps(paths, precedence, RejectAmbiguousPaths())
+	require.EqualError(t, err, "ambiguous path templates: GET /a/b/{y} and /a/{x}/c (e.g. /a/b/c)")
+	ambiguous, ok := err.(*AmbiguousPathsError)
+	require.True(t, ok)
+	require.Len(t, ambiguous.Overlaps, 1)
+}
@@ -0,0 +1,74 @@
+package routers
+
+import (
+	"regexp"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// PathParameterPatterns returns the regular expressions of the raw values of the path
+// parameters of an operation (nil for those of pathItem only) whose style prefixes them,
+// such as `;color(?:=[^;/]*)?` for a matrix parameter "color", by parameter name.
+//
+// Routers match variables with them to tell where values start and end in segments
+// such as /cars{color}{size}, the prefixes being left for the parameters' decoding.
+// Other variables match any non-empty part of a path segment.
+func PathParameterPatterns(pathItem *openapi3.PathItem, operation *openapi3.Operation) map[string]string {
+	var params []*openapi3.Parameter
+	if operation != nil {
+		for _, param := range operation.Parameters {
+			params = append(params, param.Value)
+		}
+	}
+	for _, param := range pathItem.Parameters {
+		params = append(params, param.Value)
+	}
+
+	var patterns map[string]string
+	for _, param := range params {
+		if param == nil || param.In != openapi3.ParameterInPath {
+			continue
+		}
+		if _, ok := patterns[param.Name]; ok {
+			// Overridden by the operation
+			continue
+		}
+		pattern := pathParameterPattern(param)
+		if patterns == nil {
+			patterns = make(map[string]string)
+		}
+		patterns[param.Name] = pattern
+	}
+	for name, pattern := range patterns {
+		if pattern == "" {
+			delete(patterns, name)
+		}
+	}
+	return patterns
+}
+
+func pathParameterPattern(param *openapi3.Parameter) string {
+	sm, err := param.SerializationMethod()
+	if err != nil {
+		return ""
+	}
+	var schemaType string
+	if param.Schema != nil && param.Schema.Value != nil {
+		schemaType = param.Schema.Value.Type
+	}
+	name := regexp.QuoteMeta(param.Name)
+	switch sm.Style {
+	case openapi3.SerializationMatrix:
+		switch {
+		case schemaType == "array" && sm.Explode:
+			return `(?:;` + name + `(?:=[^;/]*)?)+`
+		case schemaType == "object" && sm.Explode:
+			return `(?:;[^;/=]+(?:=[^;/]*)?)+`
+		default:
+			return `;` + name + `(?:=[^;/]*)?`
+		}
+	case openapi3.SerializationLabel:
+		return `\.[^/]*`
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathParameterPatterns(t *testing.T) {
+	explode := true
+	param := func(name, style string, explode *bool, schema *openapi3.Schema) *openapi3.ParameterRef {
+		p := openapi3.NewPathParameter(name).WithSchema(schema)
+		p.Style, p.Explode = style, explode
+		return &openapi3.ParameterRef{Value: p}
+	}
+	pathItem := &openapi3.PathItem{
+		Parameters: openapi3.Parameters{
+			param("id", "", nil, openapi3.NewIntegerSchema()),
+			param("color", "matrix", nil, openapi3.NewStringSchema()),
+			param("colors", "matrix", &explode, openapi3.NewArraySchema()),
+			param("point", "matrix", &explode, openapi3.NewObjectSchema()),
+			param("tags", "label", nil, openapi3.NewArraySchema()),
+		},
+	}
+	require.Equal(t, map[string]string{
+		"color":  `;color(?:=[^;/]*)?`,
+		"colors": `(?:;colors(?:=[^;/]*)?)+`,
+		"point":  `(?:;[^;/=]+(?:=[^;/]*)?)+`,
+		"tags":   `\.[^/]*`,
+	}, PathParameterPatterns(pathItem, nil))
+
+	// Operations override the parameters of their path
+	operation := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			param("color", "", nil, openapi3.NewStringSchema()),
+			param("id", "label", nil, openapi3.NewIntegerSchema()),
+		},
+	}
+	require.Equal(t, map[string]string{
+		"id":     `\.[^/]*`,
+		"colors": `(?:;colors(?:=[^;/]*)?)+`,
+		"point":  `(?:;[^;/=]+(?:=[^;/]*)?)+`,
+		"tags":   `\.[^/]*`,
+	}, PathParameterPatterns(pathItem, operation))
+}
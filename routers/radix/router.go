@@ -0,0 +1,296 @@
+// Package radix implements a router.
+//
+// It differs from the other routers:
+// * it has no dependencies and compiles the paths and servers of a document into a radix tree,
+// so finding a route takes time proportional to the length of the path rather than to the number of paths.
+// * it returns a new Route on each match and can be used concurrently.
+// * it handles matching routes with extensions (e.g. /books/{id}.json), several variables in a segment
+// (e.g. /reports/{year}-{month}) and matrix or label path parameters (e.g. /cars{color}{size})
+// * concrete paths take precedence over templated ones, segment by segment (e.g. /users/me over /users/{id})
+// * it can report overlapping paths and reject ambiguous ones (see routers.Option)
+// * path parameters only hold the variables of paths: those of servers are in Route.ServerVariables
+// * paths are compared once unescaped, so /café matches requests for /caf%C3%A9 and the other way around
+package radix
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Router helps link http.Request.s and an OpenAPIv3 spec
+type Router struct {
+	doc  *openapi3.T
+	root *node
+}
+
+var _ routers.Router = (*Router)(nil)
+
+// server is a compiled server of the document.
+type server struct {
+	server    *openapi3.Server
+	scheme    []token // nil when the server URL is relative
+	host      []token
+	baseNames []string // Names of the variables of the base path
+}
+
+// variables returns the values of the variables of s matched in a request URL:
+// those of its scheme when the URL has one, of its host then of its base path.
+func (s *server) variables(withScheme bool, hostValues, baseValues []string) map[string]string {
+	variables := make(map[string]string, len(s.server.Variables))
+	var names []string
+	if s.host != nil {
+		if withScheme {
+			names = variableNames(s.scheme)
+		}
+		names = append(names, variableNames(s.host)...)
+	}
+	for i, name := range names {
+		variables[name] = hostValues[i]
+	}
+	for i, name := range s.baseNames {
+		variables[name] = pathUnescape(baseValues[i])
+	}
+	return variables
+}
+
+// endpoint is a path of the document under a server.
+type endpoint struct {
+	server   *server // nil when the document has no servers
+	path     string
+	pathItem *openapi3.PathItem
+	// Names of the variables of the template of the endpoint, that of its server's base path first
+	names []string
+	// Patterns of the values of the variables by method, nil for those matching any value
+	patterns map[string][]*regexp.Regexp
+}
+
+// matches tells whether the values of the variables suit the path parameters of the operation of method.
+func (ep *endpoint) matches(method string, values []string) bool {
+	for i, pattern := range ep.patterns[method] {
+		if pattern != nil && !pattern.MatchString(values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRouter creates a radix tree router.
+// Assumes spec is .Validate()d
+func NewRouter(doc *openapi3.T, opts ...routers.Option) (routers.Router, error) {
+	if err := routers.CheckOverlaps(doc.Paths, precedence, opts...); err != nil {
+		return nil, err
+	}
+	r := &Router{doc: doc, root: &node{}}
+
+	var servers []*server
+	var bases [][]token
+	for _, s := range doc.Servers {
+		compiled, base, err := compileServer(s)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, compiled)
+		bases = append(bases, base)
+	}
+	if len(servers) == 0 {
+		servers, bases = []*server{nil}, [][]token{nil}
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for i, s := range servers {
+		for _, path := range paths {
+			tokens, err := parseTemplate(path)
+			if err != nil {
+				return nil, err
+			}
+			pathNames := variableNames(tokens)
+			tokens = append(append([]token(nil), bases[i]...), escapeLiterals(tokens)...)
+			ep := &endpoint{
+				server:   s,
+				path:     path,
+				pathItem: doc.Paths[path],
+				names:    variableNames(tokens),
+			}
+			if ep.patterns, err = compilePatterns(ep.pathItem, ep.names, len(ep.names)-len(pathNames)); err != nil {
+				return nil, err
+			}
+			r.root.insert(tokens, ep)
+		}
+	}
+	r.root.finish()
+	return r, nil
+}
+
+// precedence routes path in a tree of templates a and b only.
+func precedence(method, a, b, path string) string {
+	root := &node{}
+	for _, template := range []string{a, b} {
+		tokens, err := parseTemplate(template)
+		if err != nil {
+			return ""
+		}
+		root.insert(escapeLiterals(tokens), &endpoint{path: template})
+	}
+	root.finish()
+	accept := func(*endpoint, []string) bool { return true }
+	if ep, _ := root.lookup(escapePath(path), nil, accept); ep != nil {
+		return ep.path
+	}
+	return ""
+}
+
+// compilePatterns compiles the patterns of the values of the variables
+// named names for each operation of pathItem, the first skipped ones being server variables.
+func compilePatterns(pathItem *openapi3.PathItem, names []string, skipped int) (map[string][]*regexp.Regexp, error) {
+	var compiled map[string][]*regexp.Regexp
+	for method, operation := range pathItem.Operations() {
+		patterns := routers.PathParameterPatterns(pathItem, operation)
+		if len(patterns) == 0 {
+			continue
+		}
+		regexps := make([]*regexp.Regexp, len(names))
+		for i, name := range names[skipped:] {
+			if pattern, ok := patterns[name]; ok {
+				re, err := regexp.Compile("^(?:" + pattern + ")$")
+				if err != nil {
+					return nil, err
+				}
+				regexps[skipped+i] = re
+			}
+		}
+		if compiled == nil {
+			compiled = make(map[string][]*regexp.Regexp)
+		}
+		compiled[method] = regexps
+	}
+	return compiled, nil
+}
+
+// compileServer returns the compiled server and the template of its base path.
+func compileServer(s *openapi3.Server) (*server, []token, error) {
+	compiled := &server{server: s}
+	rest := s.URL
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme, err := parseTemplate(strings.ToLower(rest[:i]))
+		if err != nil {
+			return nil, nil, err
+		}
+		rest = rest[i+len("://"):]
+		hostEnd := strings.IndexByte(rest, '/')
+		if hostEnd < 0 {
+			hostEnd = len(rest)
+		}
+		host, err := parseTemplate(strings.ToLower(rest[:hostEnd]))
+		if err != nil {
+			return nil, nil, err
+		}
+		compiled.scheme, compiled.host = scheme, host
+		rest = rest[hostEnd:]
+	}
+	base, err := parseTemplate(strings.TrimSuffix(rest, "/"))
+	if err != nil {
+		return nil, nil, err
+	}
+	compiled.baseNames = variableNames(base)
+	return compiled, escapeLiterals(base), nil
+}
+
+// FindRoute extracts the route and parameters of an http.Request
+func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	scheme, host := strings.ToLower(req.URL.Scheme), req.URL.Host
+	if host == "" {
+		host = req.Host
+	}
+	host = strings.ToLower(host)
+
+	var (
+		serverValues    []string
+		serverURL       string
+		serverVariables map[string]string
+		methodMismatch  *endpoint
+	)
+	accept := func(ep *endpoint, values []string) bool {
+		s := ep.server
+		if s != nil && s.host != nil {
+			var ok bool
+			serverValues = serverValues[:0]
+			// The scheme of requests received by servers is unknown
+			if scheme != "" {
+				if serverValues, ok = matchTemplate(s.scheme, scheme, serverValues); !ok {
+					return false
+				}
+			}
+			if serverValues, ok = matchTemplate(s.host, host, serverValues); !ok {
+				return false
+			}
+		}
+		if ep.pathItem.GetOperation(req.Method) == nil {
+			if methodMismatch == nil {
+				methodMismatch = ep
+			}
+			return false
+		}
+		if !ep.matches(req.Method, values) {
+			return false
+		}
+		if s != nil {
+			var ok bool
+			variables := s.variables(scheme != "", serverValues, values[:len(s.baseNames)])
+			if serverURL, serverVariables, ok = routers.ResolveServer(s.server, variables); !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	ep, values := r.root.lookup(escapePath(req.URL.EscapedPath()), make([]string, 0, 8), accept)
+	if ep == nil {
+		if ep := methodMismatch; ep != nil {
+			return nil, nil, routers.NewMethodNotAllowedError(ep.path, ep.pathItem)
+		}
+		return nil, nil, routers.ErrPathNotFound
+	}
+
+	// Values of the variables of the server's base path are left to Route.ServerVariables
+	var offset int
+	if ep.server != nil {
+		offset = len(ep.server.baseNames)
+	}
+	pathParams := make(map[string]string, len(values)-offset)
+	for i, name := range ep.names[offset:] {
+		pathParams[name] = pathUnescape(values[offset+i])
+	}
+
+	var openapiServer *openapi3.Server
+	if ep.server != nil {
+		openapiServer = ep.server.server
+	}
+	return &routers.Route{
+		Spec:            r.doc,
+		Server:          openapiServer,
+		Path:            ep.path,
+		PathItem:        ep.pathItem,
+		Method:          req.Method,
+		Operation:       ep.pathItem.GetOperation(req.Method),
+		ServerURL:       serverURL,
+		ServerVariables: serverVariables,
+	}, pathParams, nil
+}
+
+func pathUnescape(value string) string {
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
@@ -0,0 +1,350 @@
+package radix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter(t *testing.T) {
+	helloDELETE := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	helloGET := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	helloHEAD := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	helloPOST := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	paramsGET := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	booksGET := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	booksPOST := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	meGET := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	userGET := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	userDELETE := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	rootGET := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	pathParameters := func(names ...string) openapi3.Parameters {
+		var params openapi3.Parameters
+		for _, name := range names {
+			params = append(params, &openapi3.ParameterRef{Value: openapi3.NewPathParameter(name)})
+		}
+		return params
+	}
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:   "MyAPI",
+			Version: "0.1",
+		},
+		Paths: openapi3.Paths{
+			"/": &openapi3.PathItem{
+				Get: rootGET,
+			},
+			"/hello": &openapi3.PathItem{
+				Delete: helloDELETE,
+				Get:    helloGET,
+				Head:   helloHEAD,
+				Post:   helloPOST,
+			},
+			"/onlyGET": &openapi3.PathItem{
+				Get: helloGET,
+			},
+			"/params/{x}/{y}/{z}": &openapi3.PathItem{
+				Get:        paramsGET,
+				Parameters: pathParameters("x", "y", "z"),
+			},
+			"/books/{bookid}": &openapi3.PathItem{
+				Get:        booksGET,
+				Parameters: pathParameters("bookid"),
+			},
+			"/books/{bookid2}.json": &openapi3.PathItem{
+				Post:       booksPOST,
+				Parameters: pathParameters("bookid2"),
+			},
+			"/users/me": &openapi3.PathItem{
+				Get: meGET,
+			},
+			"/users/{id}": &openapi3.PathItem{
+				Get:        userGET,
+				Delete:     userDELETE,
+				Parameters: pathParameters("id"),
+			},
+		},
+	}
+	err := doc.Validate(context.Background())
+	require.NoError(t, err)
+
+	expect := func(r routers.Router, method, uri string, operation *openapi3.Operation, params map[string]string) {
+		t.Helper()
+		req, err := http.NewRequest(method, uri, nil)
+		require.NoError(t, err)
+		route, pathParams, err := r.FindRoute(req)
+		if operation == nil {
+			require.Error(t, err, "%s %s", method, uri)
+			require.Nil(t, route)
+			return
+		}
+		require.NoError(t, err, "%s %s", method, uri)
+		require.True(t, route.Operation == operation, "%s %s: wrong operation", method, uri)
+		require.Equal(t, method, route.Method)
+		if params == nil {
+			params = map[string]string{}
+		}
+		require.Equal(t, params, pathParams, "%s %s", method, uri)
+	}
+
+	r, err := NewRouter(doc)
+	require.NoError(t, err)
+
+	expect(r, http.MethodGet, "/not_existing", nil, nil)
+	expect(r, http.MethodGet, "/", rootGET, nil)
+	expect(r, http.MethodDelete, "/hello", helloDELETE, nil)
+	expect(r, http.MethodGet, "/hello", helloGET, nil)
+	expect(r, http.MethodHead, "/hello", helloHEAD, nil)
+	expect(r, http.MethodPost, "/hello", helloPOST, nil)
+	expect(r, http.MethodGet, "/hello/", nil, nil)
+	expect(r, http.MethodGet, "/params/a/b/c%2Fd", paramsGET, map[string]string{
+		"x": "a",
+		"y": "b",
+		"z": "c/d",
+	})
+	expect(r, http.MethodGet, "/params/a/b/", nil, nil)
+	expect(r, http.MethodGet, "/books/War.and.Peace", booksGET, map[string]string{
+		"bookid": "War.and.Peace",
+	})
+	expect(r, http.MethodPost, "/books/War.and.Peace.json", booksPOST, map[string]string{
+		"bookid2": "War.and.Peace",
+	})
+	expect(r, http.MethodGet, "/users/me", meGET, nil)
+	expect(r, http.MethodGet, "/users/42", userGET, map[string]string{"id": "42"})
+	// Templated paths match what concrete ones don't allow
+	expect(r, http.MethodDelete, "/users/me", userDELETE, map[string]string{"id": "me"})
+
+	req, err := http.NewRequest(http.MethodPut, "/hello", nil)
+	require.NoError(t, err)
+	_, _, err = r.FindRoute(req)
+	require.True(t, errors.Is(err, routers.ErrMethodNotAllowed))
+	require.Equal(t, &routers.RouteError{
+		Reason:         routers.ErrMethodNotAllowed.Error(),
+		Path:           "/hello",
+		AllowedMethods: []string{http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPost},
+	}, err)
+	req, err = http.NewRequest(http.MethodPut, "/users/42", nil)
+	require.NoError(t, err)
+	_, _, err = r.FindRoute(req)
+	require.Equal(t, &routers.RouteError{
+		Reason:         routers.ErrMethodNotAllowed.Error(),
+		Path:           "/users/{id}",
+		AllowedMethods: []string{http.MethodDelete, http.MethodGet},
+	}, err)
+	req, err = http.NewRequest(http.MethodGet, "/nothing", nil)
+	require.NoError(t, err)
+	_, _, err = r.FindRoute(req)
+	require.Equal(t, routers.ErrPathNotFound, err)
+
+	doc.Servers = []*openapi3.Server{
+		{URL: "https://www.example.com/api/v1"},
+		{URL: "{scheme}://{d0}.{d1}.com/api/v1/", Variables: map[string]*openapi3.ServerVariable{
+			"d0":     {Default: "www"},
+			"d1":     {Default: "example", Enum: []string{"example"}},
+			"scheme": {Default: "https", Enum: []string{"https", "http"}},
+		}},
+		{URL: "/relative/{version}", Variables: map[string]*openapi3.ServerVariable{
+			"version": {Default: "v2"},
+		}},
+	}
+	err = doc.Validate(context.Background())
+	require.NoError(t, err)
+	r, err = NewRouter(doc)
+	require.NoError(t, err)
+	expect(r, http.MethodGet, "/hello", nil, nil)
+	expect(r, http.MethodGet, "/api/v1/hello", nil, nil)
+	expect(r, http.MethodGet, "https://www.example.com/hello", nil, nil)
+	expect(r, http.MethodGet, "https://www.example.com/api/v1/hello", helloGET, nil)
+	// Server variables take enumerated values only
+	expect(r, http.MethodGet, "https://domain0.domain1.com/api/v1/hello", nil, nil)
+	expect(r, http.MethodGet, "ftp://domain0.example.com/api/v1/hello", nil, nil)
+	// Server variables are not path parameters
+	expect(r, http.MethodGet, "https://domain0.example.com/api/v1/hello", helloGET, nil)
+	expect(r, http.MethodGet, "http://any.host/relative/v3/users/42", userGET, map[string]string{"id": "42"})
+
+	// Servers receive requests without scheme
+	req, err = http.NewRequest(http.MethodGet, "/api/v1/hello", nil)
+	require.NoError(t, err)
+	req.Host = "www.example.com"
+	route, pathParams, err := r.FindRoute(req)
+	require.NoError(t, err)
+	require.Equal(t, doc.Servers[0], route.Server)
+	require.Equal(t, "https://www.example.com/api/v1", route.ServerURL)
+	require.Empty(t, route.ServerVariables)
+	require.Empty(t, pathParams)
+
+	// Variables missing from the request take their default value
+	req, err = http.NewRequest(http.MethodGet, "/api/v1/hello", nil)
+	require.NoError(t, err)
+	req.Host = "eu.example.com"
+	route, _, err = r.FindRoute(req)
+	require.NoError(t, err)
+	require.Equal(t, doc.Servers[1], route.Server)
+	require.Equal(t, "https://eu.example.com/api/v1/", route.ServerURL)
+	require.Equal(t, map[string]string{"scheme": "https", "d0": "eu", "d1": "example"}, route.ServerVariables)
+
+	req, err = http.NewRequest(http.MethodGet, "http://any.host/relative/v3/users/42", nil)
+	require.NoError(t, err)
+	route, _, err = r.FindRoute(req)
+	require.NoError(t, err)
+	require.Equal(t, "/relative/v3", route.ServerURL)
+	require.Equal(t, map[string]string{"version": "v3"}, route.ServerVariables)
+}
+
+func TestRouterInvalidTemplates(t *testing.T) {
+	for _, path := range []string{"/a/{b", "/a/b}", "/a/{}", "/a/{b{c}}"} {
+		_, err := NewRouter(&openapi3.T{Paths: openapi3.Paths{path: &openapi3.PathItem{}}})
+		require.Error(t, err, path)
+	}
+}
+
+func TestRouterEscapedPaths(t *testing.T) {
+	getOperation := &openapi3.Operation{Responses: openapi3.NewResponses()}
+	doc := &openapi3.T{
+		Servers: openapi3.Servers{{URL: "/v1 beta"}},
+		Paths: openapi3.Paths{
+			"/café/{name}":      &openapi3.PathItem{Get: getOperation},
+			"/a:b/{id}":         &openapi3.PathItem{Get: getOperation},
+			"/files/{path}.txt": &openapi3.PathItem{Get: getOperation},
+		},
+	}
+	r, err := NewRouter(doc)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		url        string
+		path       string
+		pathParams map[string]string
+	}{
+		{"/v1%20beta/caf%C3%A9/cr%C3%A8me", "/café/{name}", map[string]string{"name": "crème"}},
+		{"/v1%20beta/caf%c3%a9/latte", "/café/{name}", map[string]string{"name": "latte"}},
+		{"/v1%20beta/a:b/1", "/a:b/{id}", map[string]string{"id": "1"}},
+		{"/v1%20beta/a%3Ab/1", "/a:b/{id}", map[string]string{"id": "1"}},
+		{"/v1%20beta/files/a%2Fb.txt", "/files/{path}.txt", map[string]string{"path": "a/b"}},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		route, pathParams, err := r.FindRoute(req)
+		require.NoError(t, err, tt.url)
+		require.Equal(t, tt.path, route.Path, tt.url)
+		require.Equal(t, tt.pathParams, pathParams, tt.url)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1%20beta/cafe/latte", nil)
+	_, _, err = r.FindRoute(req)
+	require.Equal(t, routers.ErrPathNotFound, err)
+}
+
+func TestRouterConcurrentFindRoute(t *testing.T) {
+	doc := &openapi3.T{
+		Paths: openapi3.Paths{
+			"/items/{id}": &openapi3.PathItem{
+				Get:    &openapi3.Operation{Responses: openapi3.NewResponses()},
+				Delete: &openapi3.Operation{Responses: openapi3.NewResponses()},
+			},
+		},
+	}
+	r, err := NewRouter(doc)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		method := http.MethodGet
+		if i%2 == 0 {
+			method = http.MethodDelete
+		}
+		wg.Add(1)
+		go func(i int, method string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := fmt.Sprintf("%d-%d", i, j)
+				req, _ := http.NewRequest(method, "/items/"+id, nil)
+				route, pathParams, err := r.FindRoute(req)
+				if err != nil || route.Method != method || route.Operation != doc.Paths["/items/{id}"].GetOperation(method) || pathParams["id"] != id {
+					t.Errorf("%s %s: unexpected route %+v, %v, %v", method, id, route, pathParams, err)
+					return
+				}
+			}
+		}(i, method)
+	}
+	wg.Wait()
+}
+
+// benchmarkDoc returns a document with n paths of 4 segments, half of them templated.
+func benchmarkDoc(n int) *openapi3.T {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "Benchmark", Version: "0.1"},
+		Paths:   make(openapi3.Paths, n),
+	}
+	for i := 0; i < n; i++ {
+		path := fmt.Sprintf("/resource%d/{id}/sub%d/{subId}", i/2, i)
+		params := openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewStringSchema())},
+			&openapi3.ParameterRef{Value: openapi3.NewPathParameter("subId").WithSchema(openapi3.NewStringSchema())},
+		}
+		if i%2 == 0 {
+			path = fmt.Sprintf("/resource%d/static/sub%d/list", i/2, i)
+			params = nil
+		}
+		doc.Paths[path] = &openapi3.PathItem{
+			Get:        &openapi3.Operation{Responses: openapi3.NewResponses()},
+			Parameters: params,
+		}
+	}
+	return doc
+}
+
+func BenchmarkFindRoute(b *testing.B) {
+	const n = 800
+	doc := benchmarkDoc(n)
+	if err := doc.Validate(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+	requests := []*http.Request{
+		newGetRequest(b, "/resource0/static/sub0/list"),
+		newGetRequest(b, fmt.Sprintf("/resource%d/42/sub%d/7", (n-1)/2, n-1)),
+	}
+
+	for _, bench := range []struct {
+		name      string
+		newRouter func(*openapi3.T, ...routers.Option) (routers.Router, error)
+	}{
+		{"radix", NewRouter},
+		{"gorillamux", gorillamux.NewRouter},
+		{"legacy", legacy.NewRouter},
+	} {
+		router, err := bench.newRouter(doc)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, req := range requests {
+			b.Run(bench.name+req.URL.Path, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, _, err := router.FindRoute(req); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func newGetRequest(b *testing.B, path string) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return req
+}
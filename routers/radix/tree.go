@@ -0,0 +1,261 @@
+package radix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// token is a part of a template, such as "/books/{id}.json":
+// either literal text or the name of a variable.
+type token struct {
+	text     string
+	variable bool
+}
+
+// parseTemplate splits a template into literal text and variables.
+func parseTemplate(template string) ([]token, error) {
+	var tokens []token
+	for s := template; s != ""; {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			if strings.IndexByte(s, '}') >= 0 {
+				return nil, fmt.Errorf("unbalanced braces in %q", template)
+			}
+			tokens = append(tokens, token{text: s})
+			break
+		}
+		if i > 0 {
+			if strings.IndexByte(s[:i], '}') >= 0 {
+				return nil, fmt.Errorf("unbalanced braces in %q", template)
+			}
+			tokens = append(tokens, token{text: s[:i]})
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return nil, fmt.Errorf("unbalanced braces in %q", template)
+		}
+		name := s[i+1 : i+j]
+		if name == "" || strings.ContainsAny(name, "{/") {
+			return nil, fmt.Errorf("invalid variable %q in %q", s[i:i+j+1], template)
+		}
+		tokens = append(tokens, token{text: name, variable: true})
+		s = s[i+j+1:]
+	}
+	return tokens, nil
+}
+
+// escapeLiterals returns tokens with their literal text escaped as by escapePath.
+func escapeLiterals(tokens []token) []token {
+	escaped := make([]token, 0, len(tokens))
+	for _, tok := range tokens {
+		if !tok.variable {
+			tok.text = escapePath(tok.text)
+		}
+		escaped = append(escaped, tok)
+	}
+	return escaped
+}
+
+// escapePath returns the canonical escaping of the path s, so that paths escaped differently
+// (e.g. /café, /caf%C3%A9 and /caf%c3%a9) compare equal: escaped bytes are decoded
+// then the bytes path segments can't hold as is are escaped again, encoded slashes included.
+func escapePath(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c, escaped := s[i], false
+		if c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			c, escaped = unhex(s[i+1])<<4|unhex(s[i+2]), true
+			i += 2
+		}
+		if (c == '/' && !escaped) || (c != '/' && isPathChar(c)) {
+			sb.WriteByte(c)
+		} else {
+			const hex = "0123456789ABCDEF"
+			sb.WriteByte('%')
+			sb.WriteByte(hex[c>>4])
+			sb.WriteByte(hex[c&15])
+		}
+	}
+	return sb.String()
+}
+
+// isPathChar tells whether c can appear unescaped in a path segment (see RFC 3986 pchar).
+func isPathChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$&'()*+,;=:@", c) >= 0
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// variableNames returns the names of the variables among tokens.
+func variableNames(tokens []token) []string {
+	var names []string
+	for _, tok := range tokens {
+		if tok.variable {
+			names = append(names, tok.text)
+		}
+	}
+	return names
+}
+
+// matchTemplate matches s against tokens, appending the values of the variables to values.
+// Variables match non-empty strings, the longest first.
+func matchTemplate(tokens []token, s string, values []string) ([]string, bool) {
+	if len(tokens) == 0 {
+		return values, s == ""
+	}
+	tok := tokens[0]
+	if !tok.variable {
+		if !strings.HasPrefix(s, tok.text) {
+			return nil, false
+		}
+		return matchTemplate(tokens[1:], s[len(tok.text):], values)
+	}
+	for end := len(s); end > 0; end-- {
+		if vs, ok := matchTemplate(tokens[1:], s[end:], append(values, s[:end])); ok {
+			return vs, true
+		}
+	}
+	return nil, false
+}
+
+// node is a node of a radix tree of path templates.
+//
+// Static children are reached through edges labelled with literal text,
+// no two of which share a first byte. The variable child is reached by
+// a non-empty part of a path segment, whatever its name in the templates.
+type node struct {
+	prefix    string
+	indices   string // First bytes of the prefixes of static
+	static    []*node
+	variable  *node
+	partial   bool // Whether a variable node can be followed by more of its segment
+	endpoints []*endpoint
+}
+
+// insert adds the endpoint of the template tokens below n.
+func (n *node) insert(tokens []token, ep *endpoint) {
+	if len(tokens) == 0 {
+		n.endpoints = append(n.endpoints, ep)
+		return
+	}
+	if tok := tokens[0]; tok.variable {
+		if n.variable == nil {
+			n.variable = &node{}
+		}
+		n.variable.insert(tokens[1:], ep)
+		return
+	}
+	n.insertStatic(tokens[0].text, tokens[1:], ep)
+}
+
+func (n *node) insertStatic(text string, rest []token, ep *endpoint) {
+	if i := strings.IndexByte(n.indices, text[0]); i >= 0 {
+		child := n.static[i]
+		common := commonPrefixLength(child.prefix, text)
+		if common < len(child.prefix) {
+			// Split the edge where the texts diverge
+			suffix := *child
+			suffix.prefix = child.prefix[common:]
+			*child = node{
+				prefix:  child.prefix[:common],
+				indices: suffix.prefix[:1],
+				static:  []*node{&suffix},
+			}
+		}
+		if common == len(text) {
+			child.insert(rest, ep)
+		} else {
+			child.insertStatic(text[common:], rest, ep)
+		}
+		return
+	}
+	child := &node{prefix: text}
+	n.indices += text[:1]
+	n.static = append(n.static, child)
+	child.insert(rest, ep)
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// finish computes what lookups need once all the templates are inserted.
+func (n *node) finish() {
+	for _, child := range n.static {
+		child.finish()
+	}
+	if v := n.variable; v != nil {
+		v.finish()
+		v.partial = v.variable != nil
+		for _, child := range v.static {
+			if child.prefix[0] != '/' {
+				v.partial = true
+			}
+		}
+	}
+}
+
+// lookup returns the first endpoint below n matching path that accept accepts,
+// with the values of the variables along the way appended to values.
+// Static text takes precedence over variables, and the text following a variable
+// in its segment over the variable matching the whole segment: variables otherwise
+// match as much of their segment as possible.
+func (n *node) lookup(path string, values []string, accept func(*endpoint, []string) bool) (*endpoint, []string) {
+	if path == "" {
+		for _, ep := range n.endpoints {
+			if accept(ep, values) {
+				return ep, values
+			}
+		}
+		return nil, nil
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i >= 0 {
+		child := n.static[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if ep, vs := child.lookup(path[len(child.prefix):], values, accept); ep != nil {
+				return ep, vs
+			}
+		}
+	}
+
+	if v := n.variable; v != nil {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if v.partial {
+			for i := end - 1; i > 0; i-- {
+				if ep, vs := v.lookup(path[i:], append(values, path[:i]), accept); ep != nil {
+					return ep, vs
+				}
+			}
+		}
+		if ep, vs := v.lookup(path[end:], append(values, path[:end]), accept); ep != nil {
+			return ep, vs
+		}
+	}
+	return nil, nil
+}
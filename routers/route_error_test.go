@@ -0,0 +1,54 @@
+package routers_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/getkin/kin-openapi/routers/radix"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteErrors(t *testing.T) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "MyAPI", Version: "0.1"},
+		Paths: openapi3.Paths{
+			"/pets": &openapi3.PathItem{
+				Get:  &openapi3.Operation{Responses: openapi3.NewResponses()},
+				Post: &openapi3.Operation{Responses: openapi3.NewResponses()},
+			},
+		},
+	}
+
+	for name, newRouter := range map[string]func(*openapi3.T, ...routers.Option) (routers.Router, error){
+		"gorillamux": gorillamux.NewRouter,
+		"legacy":     legacyrouter.NewRouter,
+		"radix":      radix.NewRouter,
+	} {
+		t.Run(name, func(t *testing.T) {
+			router, err := newRouter(doc)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodDelete, "/pets", nil)
+			require.NoError(t, err)
+			_, _, err = router.FindRoute(req)
+			require.True(t, errors.Is(err, routers.ErrMethodNotAllowed))
+			require.False(t, errors.Is(err, routers.ErrPathNotFound))
+			var routeErr *routers.RouteError
+			require.True(t, errors.As(err, &routeErr))
+			require.Equal(t, "/pets", routeErr.Path)
+			require.Equal(t, []string{http.MethodGet, http.MethodPost}, routeErr.AllowedMethods)
+
+			req, err = http.NewRequest(http.MethodGet, "/owners", nil)
+			require.NoError(t, err)
+			_, _, err = router.FindRoute(req)
+			require.True(t, errors.Is(err, routers.ErrPathNotFound))
+			require.False(t, errors.Is(err, routers.ErrMethodNotAllowed))
+		})
+	}
+}
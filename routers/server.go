@@ -0,0 +1,35 @@
+package routers
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ResolveServer returns the URL of server with its variables replaced by their values,
+// the default value of those missing from values, along with the values of all its variables.
+// It fails when one of values isn't among the enumerated values of its variable.
+func ResolveServer(server *openapi3.Server, values map[string]string) (string, map[string]string, bool) {
+	serverURL := server.URL
+	variables := make(map[string]string, len(server.Variables))
+	for name, variable := range server.Variables {
+		value, ok := values[name]
+		if !ok {
+			value = variable.Default
+		} else if len(variable.Enum) != 0 && !isEnumerated(variable.Enum, value) {
+			return "", nil, false
+		}
+		variables[name] = value
+		serverURL = strings.Replace(serverURL, "{"+name+"}", value, -1)
+	}
+	return serverURL, variables, true
+}
+
+func isEnumerated(enum []string, value string) bool {
+	for _, v := range enum {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
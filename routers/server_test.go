@@ -0,0 +1,32 @@
+package routers
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveServer(t *testing.T) {
+	server := &openapi3.Server{
+		URL: "{scheme}://{region}.example.com/api/{version}",
+		Variables: map[string]*openapi3.ServerVariable{
+			"scheme":  {Default: "https", Enum: []string{"http", "https"}},
+			"region":  {Default: "eu"},
+			"version": {Default: "v1", Enum: []string{"v1", "v2"}},
+		},
+	}
+
+	serverURL, variables, ok := ResolveServer(server, map[string]string{"region": "us", "version": "v2"})
+	require.True(t, ok)
+	require.Equal(t, "https://us.example.com/api/v2", serverURL)
+	require.Equal(t, map[string]string{"scheme": "https", "region": "us", "version": "v2"}, variables)
+
+	_, _, ok = ResolveServer(server, map[string]string{"version": "v3"})
+	require.False(t, ok)
+
+	serverURL, variables, ok = ResolveServer(&openapi3.Server{URL: "/api"}, nil)
+	require.True(t, ok)
+	require.Equal(t, "/api", serverURL)
+	require.Empty(t, variables)
+}
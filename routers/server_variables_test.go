@@ -0,0 +1,60 @@
+package routers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/getkin/kin-openapi/routers/radix"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerVariablesCollidingWithPathParameters(t *testing.T) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "MyAPI", Version: "0.1"},
+		Servers: openapi3.Servers{
+			{URL: "https://{id}.example.com/{version}", Variables: map[string]*openapi3.ServerVariable{
+				"id":      {Default: "www"},
+				"version": {Default: "v1"},
+			}},
+		},
+		Paths: openapi3.Paths{
+			"/users/{id}": &openapi3.PathItem{
+				Get: &openapi3.Operation{
+					Parameters: openapi3.Parameters{{Value: openapi3.NewPathParameter("id").WithSchema(openapi3.NewStringSchema())}},
+					Responses:  openapi3.NewResponses(),
+				},
+			},
+		},
+	}
+	err := doc.Validate(context.Background())
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		newRouter  func(*openapi3.T, ...routers.Option) (routers.Router, error)
+		pathParams map[string]string
+	}{
+		// Deprecated: these routers also return server variables as path parameters
+		"gorillamux": {gorillamux.NewRouter, map[string]string{"id": "42", "version": "v2"}},
+		"legacy":     {legacyrouter.NewRouter, map[string]string{"id": "42", "version": "v2"}},
+		"radix":      {radix.NewRouter, map[string]string{"id": "42"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router, err := test.newRouter(doc)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, "https://tenant.example.com/v2/users/42", nil)
+			require.NoError(t, err)
+			route, pathParams, err := router.FindRoute(req)
+			require.NoError(t, err)
+			require.Equal(t, test.pathParams, pathParams)
+			require.Equal(t, map[string]string{"id": "tenant", "version": "v2"}, route.ServerVariables)
+			require.Equal(t, "https://tenant.example.com/v2", route.ServerURL)
+		})
+	}
+}
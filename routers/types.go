@@ -2,6 +2,7 @@ package routers
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -19,17 +20,52 @@ type Route struct {
 	PathItem  *openapi3.PathItem
 	Method    string
 	Operation *openapi3.Operation
+
+	// ServerURL is the URL of Server with its variables replaced by their values
+	ServerURL string
+	// ServerVariables are the values of the variables of Server: those in the request's URL,
+	// or their default when it doesn't tell (e.g. the scheme of requests received by servers)
+	ServerVariables map[string]string
 }
 
 // ErrPathNotFound is returned when no route match is found
-var ErrPathNotFound error = &RouteError{"no matching operation was found"}
+var ErrPathNotFound error = &RouteError{Reason: "no matching operation was found"}
 
-// ErrMethodNotAllowed is returned when no method of the matched route matches
-var ErrMethodNotAllowed error = &RouteError{"method not allowed"}
+// ErrMethodNotAllowed is returned when no method of the matched route matches.
+// Routers never return it as is but as a new RouteError carrying the matched path and its methods:
+// use errors.Is rather than == to recognize it.
+var ErrMethodNotAllowed error = &RouteError{Reason: "method not allowed"}
 
 // RouteError describes Router errors
 type RouteError struct {
 	Reason string
+	// Path is the template of the path matched by the request, if any
+	Path string
+	// AllowedMethods are the sorted methods of the operations of Path,
+	// e.g. for the Allow header of a "405 Method Not Allowed" response
+	AllowedMethods []string
+}
+
+// NewMethodNotAllowedError returns an ErrMethodNotAllowed for a request
+// matching path, whose pathItem has no operation for the request's method.
+func NewMethodNotAllowedError(path string, pathItem *openapi3.PathItem) *RouteError {
+	operations := pathItem.Operations()
+	methods := make([]string, 0, len(operations))
+	for method := range operations {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return &RouteError{
+		Reason:         ErrMethodNotAllowed.Error(),
+		Path:           path,
+		AllowedMethods: methods,
+	}
 }
 
 func (e *RouteError) Error() string { return e.Reason }
+
+// Is tells whether target is a RouteError with the same reason, such as ErrMethodNotAllowed.
+func (e *RouteError) Is(target error) bool {
+	t, ok := target.(*RouteError)
+	return ok && t.Reason == e.Reason
+}